@@ -0,0 +1,105 @@
+// Package alert detects threshold crossings in sensor readings and
+// notifies external endpoints such as webhooks.
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// Event is a single rising-edge threshold crossing.
+type Event struct {
+	Key       string    `json:"sensor"`
+	Temp      float64   `json:"temp"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"timestamp"`
+}
+
+// Detector tracks per-sensor crit state and reports only the rising edge,
+// so a sensor sitting above crit produces one event, not one per poll.
+type Detector struct {
+	inCrit map[string]bool
+}
+
+// NewDetector creates an edge detector with every sensor assumed below crit.
+func NewDetector() *Detector {
+	return &Detector{inCrit: make(map[string]bool)}
+}
+
+// Update feeds a poll's readings and returns sensors that crossed crit
+// since the previous poll.
+func (d *Detector) Update(readings []sensor.Reading, t time.Time) []Event {
+	var events []Event
+	for _, r := range readings {
+		if !r.HasCrit {
+			continue
+		}
+		key := r.Key()
+		above := r.Temp >= r.Crit
+		if above && !d.inCrit[key] {
+			events = append(events, Event{Key: key, Temp: r.Temp, Threshold: r.Crit, Time: t})
+		}
+		d.inCrit[key] = above
+	}
+	return events
+}
+
+// Webhook POSTs events as JSON to a URL, rate-limited per sensor.
+type Webhook struct {
+	URL         string
+	MinInterval time.Duration
+	Client      *http.Client
+	last        map[string]time.Time
+}
+
+// NewWebhook creates a webhook notifier that sends at most one event per
+// sensor every 10 minutes.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:         url,
+		MinInterval: 10 * time.Minute,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		last:        make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether an event for this sensor may be sent now, and if
+// so records the send time.
+func (w *Webhook) Allow(e Event) bool {
+	if last, ok := w.last[e.Key]; ok && e.Time.Sub(last) < w.MinInterval {
+		return false
+	}
+	w.last[e.Key] = e.Time
+	return true
+}
+
+// Send POSTs the event. The payload carries a "text" field so Slack,
+// Discord-compatible and ntfy endpoints show a readable message.
+func (w *Webhook) Send(e Event) error {
+	payload := struct {
+		Event
+		Text string `json:"text"`
+	}{
+		Event: e,
+		Text:  fmt.Sprintf("%s reached %.1f°C (crit %.1f°C)", e.Key, e.Temp, e.Threshold),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
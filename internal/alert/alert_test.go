@@ -0,0 +1,208 @@
+package alert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luki/sensors/internal/history"
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+)
+
+func TestDetectorRisingEdge(t *testing.T) {
+	d := NewDetector()
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	r := sensor.Reading{Chip: "smart-sda", Label: "Drive Temp", Crit: 60, HasCrit: true}
+
+	temps := []float64{55, 61, 62, 58, 60}
+	var got []int
+	for i, temp := range temps {
+		r.Temp = temp
+		if events := d.Update([]sensor.Reading{r}, now.Add(time.Duration(i)*time.Second)); len(events) > 0 {
+			got = append(got, i)
+		}
+	}
+
+	if len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Errorf("expected edges at polls 1 and 4, got %v", got)
+	}
+}
+
+func TestWebhookRateLimit(t *testing.T) {
+	w := NewWebhook("http://localhost")
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	e := Event{Key: "smart-sda/Drive Temp", Time: now}
+
+	if !w.Allow(e) {
+		t.Fatal("first event should be allowed")
+	}
+	e.Time = now.Add(time.Minute)
+	if w.Allow(e) {
+		t.Error("event within MinInterval should be suppressed")
+	}
+	e.Time = now.Add(11 * time.Minute)
+	if !w.Allow(e) {
+		t.Error("event after MinInterval should be allowed")
+	}
+}
+
+func TestFanFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		c    ChipThermals
+		want string
+	}{
+		{"steady", ChipThermals{TempRate: 0.1, HasFan: true, FanSpeed: 1200}, ""},
+		{"fast rise, fan spinning up", ChipThermals{TempRate: 3, HasFan: true, FanSpeed: 1500, FanRate: 200}, ""},
+		{"fast rise, fan stopped", ChipThermals{TempRate: 3, HasFan: true, FanSpeed: 0}, "possible fan failure"},
+		{"fast rise, fan dropping", ChipThermals{TempRate: 3, HasFan: true, FanSpeed: 600, FanRate: -150}, "possible fan failure"},
+		{"fast rise, no fan data", ChipThermals{TempRate: 3}, ""},
+		{"sustained rise, no fan data", ChipThermals{TempRate: 0.5, Rise: 20}, "sustained rapid temp rise"},
+	}
+	for _, tt := range tests {
+		got, ok := FanFailure(tt.c)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("%s: got (%q, %v), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRise(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var pts []history.Point
+	for i := 0; i <= 90; i++ {
+		pts = append(pts, history.Point{Temp: 40 + float64(i)/3, Time: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	if got := Rise(pts, time.Minute); got != 20 {
+		t.Errorf("Rise over 60s: got %f, want 20", got)
+	}
+	if got := Rise(pts[:30], time.Minute); got != 0 {
+		t.Errorf("Rise with 30s of data: got %f, want 0", got)
+	}
+}
+
+func TestCohortOutliers(t *testing.T) {
+	cores := func(temps ...float64) []sensor.Reading {
+		var rs []sensor.Reading
+		for i, v := range temps {
+			rs = append(rs, sensor.Reading{Chip: "coretemp-isa-0000", Label: fmt.Sprintf("Core %d", i), Temp: v})
+		}
+		return rs
+	}
+
+	rs := cores(52, 53, 51, 52, 54, 52, 71, 53)
+	got := CohortOutliers(rs, 2)
+	if len(got) != 1 || !got["coretemp-isa-0000/Core 6"] {
+		t.Errorf("hot core: got %v, want only Core 6", got)
+	}
+	if got := CohortOutliers(rs, 0); got != nil {
+		t.Errorf("sigma 0 should disable, got %v", got)
+	}
+
+	// Small groups: the hot sensor is compared with the others only
+	if got := CohortOutliers(cores(45, 46, 47, 95), 2); len(got) != 1 || !got["coretemp-isa-0000/Core 3"] {
+		t.Errorf("4 sensors, one hot: got %v, want only Core 3", got)
+	}
+	if got := CohortOutliers(cores(50, 50, 50, 50, 80), 2); len(got) != 1 || !got["coretemp-isa-0000/Core 4"] {
+		t.Errorf("5 sensors, one hot: got %v, want only Core 4", got)
+	}
+	if got := CohortOutliers(cores(45, 46, 47, 48), 2); got != nil {
+		t.Errorf("4 sensors, even spread: got %v, want none", got)
+	}
+
+	// A tight spread is not flagged however many deviations it is
+	if got := CohortOutliers(cores(50, 50, 50, 50, 50, 50, 50, 52), 2); got != nil {
+		t.Errorf("2°C over identical siblings: got %v, want none", got)
+	}
+	// Sensors that normally run apart are not siblings: an NVMe drive's
+	// Sensor 2 sits well above its Composite, a GPU junction above edge
+	nvme := []sensor.Reading{
+		{Chip: "nvme-pci-0100", Label: "Composite", Temp: 36.85},
+		{Chip: "nvme-pci-0100", Label: "Sensor 1", Temp: 36.85},
+		{Chip: "nvme-pci-0100", Label: "Sensor 2", Temp: 49.85},
+	}
+	if got := CohortOutliers(nvme, 2); got != nil {
+		t.Errorf("NVMe Composite/Sensor 1/Sensor 2: got %v, want none", got)
+	}
+	gpu := []sensor.Reading{
+		{Chip: "amdgpu-pci-0300", Label: "edge", Temp: 55},
+		{Chip: "amdgpu-pci-0300", Label: "junction", Temp: 72},
+		{Chip: "amdgpu-pci-0300", Label: "mem", Temp: 56},
+	}
+	if got := CohortOutliers(gpu, 2); got != nil {
+		t.Errorf("amdgpu edge/junction/mem: got %v, want none", got)
+	}
+	// Cores are still compared when the chip also has a package sensor
+	mixed := append(cores(50, 50, 50, 70), sensor.Reading{Chip: "coretemp-isa-0000", Label: "Package id 0", Temp: 72})
+	if got := CohortOutliers(mixed, 2); len(got) != 1 || !got["coretemp-isa-0000/Core 3"] {
+		t.Errorf("cores with a package sensor: got %v, want only Core 3", got)
+	}
+
+	// Too few temperatures to compare, once the fan is left out
+	fan := sensor.Reading{Chip: "coretemp-isa-0000", Label: "fan1", Temp: 1200, Unit: "RPM"}
+	if got := CohortOutliers(append(cores(45, 80), fan), 1); got != nil {
+		t.Errorf("two temps and a fan: got %v, want none", got)
+	}
+}
+
+func TestCritHook(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	r := sensor.Reading{Chip: "coretemp-isa-0000", Label: "Package id 0", Temp: 101, Crit: 100, HasCrit: true}
+	h := NewCritHook("true", 30*time.Second)
+
+	if due := h.Update([]sensor.Reading{r}, base); len(due) != 0 {
+		t.Fatal("a first reading above crit should not run the command yet")
+	}
+	// A dip below crit restarts the sustain timer
+	cool := r
+	cool.Temp = 95
+	h.Update([]sensor.Reading{cool}, base.Add(10*time.Second))
+	if due := h.Update([]sensor.Reading{r}, base.Add(35*time.Second)); len(due) != 0 {
+		t.Fatal("the timer should have restarted after the dip")
+	}
+	due := h.Update([]sensor.Reading{r}, base.Add(65*time.Second))
+	if len(due) != 1 || due[0].Key != r.Key() || due[0].Temp != 101 {
+		t.Fatalf("after 30s above crit: got %+v", due)
+	}
+	if due := h.Update([]sensor.Reading{r}, base.Add(5*time.Minute)); len(due) != 0 {
+		t.Error("a run within MinInterval should be suppressed")
+	}
+	if due := h.Update([]sensor.Reading{r}, base.Add(12*time.Minute)); len(due) != 1 {
+		t.Error("still above crit after MinInterval should run again")
+	}
+}
+
+func TestCritHookRun(t *testing.T) {
+	dir := t.TempDir()
+	store.SetDataDir(dir)
+	defer store.SetDataDir("")
+
+	out := filepath.Join(dir, "hook.out")
+	h := NewCritHook(`printf '%s %s %s' "$1" "$2" "$SENSORS_CRIT" > `+out, 0)
+	e := Event{Key: "coretemp-isa-0000/Package id 0", Temp: 101.25, Threshold: 100, Time: time.Now()}
+	if err := h.Run(e); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "coretemp-isa-0000/Package id 0 101.2 100.0" {
+		t.Errorf("command saw %q", got)
+	}
+	events, err := store.LoadEvents(e.Time.In(store.Location()).Format("2006-01-02"))
+	if err != nil || len(events) != 2 {
+		t.Fatalf("event log: %+v, %v", events, err)
+	}
+	for i, suffix := range []string{"(running)", "(ok)"} {
+		if l := events[i].Label; !strings.HasPrefix(l, "on-crit coretemp-isa-0000/Package id 0 101.2°C") || !strings.HasSuffix(l, suffix) {
+			t.Errorf("event %d: %q, want it to end in %s", i, l, suffix)
+		}
+	}
+
+	h.Command = "echo overheated >&2; exit 3"
+	if err := h.Run(e); err == nil || !strings.Contains(err.Error(), "overheated") {
+		t.Errorf("failing command: got %v", err)
+	}
+}
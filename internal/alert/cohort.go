@@ -0,0 +1,69 @@
+package alert
+
+import (
+	"math"
+	"strings"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// Cohort heuristic thresholds.
+const (
+	CohortMinSize = 3   // like-named temperature sensors a group needs before any is compared with them
+	CohortMinGap  = 3.0 // °C above the mean a reading must also be, so near-identical siblings are not flagged
+)
+
+// CohortOutliers returns the keys of temperature readings more than sigma
+// standard deviations above the mean of their like-named siblings, such as
+// one core of a CPU running much hotter than the others. Siblings share a
+// label up to a trailing number (Core 0, Core 1, ...), so sensors that
+// normally run apart, like an NVMe Composite and its Sensor 2 or a GPU
+// junction and edge, are never compared. Each reading is compared with the
+// others only (leave-one-out): counting it in its own mean and deviation
+// would cap its score at (n-1)/√n, so small groups could never flag
+// anything. Fan, power and other readings with a unit are left out.
+// sigma <= 0 disables it.
+func CohortOutliers(readings []sensor.Reading, sigma float64) map[string]bool {
+	if sigma <= 0 {
+		return nil
+	}
+	cohorts := make(map[string][]sensor.Reading)
+	for _, r := range readings {
+		if r.Unit == "" {
+			stem := cohortStem(r.Label)
+			cohorts[stem] = append(cohorts[stem], r)
+		}
+	}
+
+	var out map[string]bool
+	for _, temps := range cohorts {
+		if len(temps) < CohortMinSize {
+			continue
+		}
+		var sum, sumSq float64
+		for _, r := range temps {
+			sum += r.Temp
+			sumSq += r.Temp * r.Temp
+		}
+		others := float64(len(temps) - 1)
+
+		for _, r := range temps {
+			mean := (sum - r.Temp) / others
+			variance := (sumSq-r.Temp*r.Temp)/others - mean*mean
+			sd := math.Sqrt(math.Max(variance, 0))
+			if d := r.Temp - mean; d > sigma*sd && d >= CohortMinGap {
+				if out == nil {
+					out = make(map[string]bool)
+				}
+				out[r.Key()] = true
+			}
+		}
+	}
+	return out
+}
+
+// cohortStem is a label without its trailing number, e.g. "Core" for
+// "Core 7" and "Tccd" for "Tccd2".
+func cohortStem(label string) string {
+	return strings.TrimRight(label, "0123456789 ")
+}
@@ -0,0 +1,56 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/luki/sensors/internal/history"
+)
+
+// Fan-failure heuristic thresholds.
+const (
+	FanTempRate     = 2.0              // °C/s temp rise that, with a stalled fan, suggests failure
+	SustainedRise   = 15.0             // °C gained over SustainedWindow without fan data
+	SustainedWindow = 60 * time.Second // window for the sustained-rise check
+)
+
+// ChipThermals summarizes one chip's temperature trend and, when the chip
+// reports one, its fan.
+type ChipThermals struct {
+	TempRate float64 // fastest °C/s among the chip's temperature sensors
+	Rise     float64 // largest °C gained over SustainedWindow
+	HasFan   bool
+	FanSpeed float64 // RPM or percent, whichever the chip reports
+	FanRate  float64 // change in FanSpeed per second
+}
+
+// FanFailure reports a warning when a chip heats quickly while its fan is
+// stopped or slowing down. Without fan data it still flags a sustained
+// rapid rise, which is the same symptom seen from the temperature side.
+func FanFailure(c ChipThermals) (string, bool) {
+	if c.HasFan && c.TempRate >= FanTempRate && (c.FanSpeed <= 0 || c.FanRate < 0) {
+		return "possible fan failure", true
+	}
+	if c.Rise >= SustainedRise {
+		return "sustained rapid temp rise", true
+	}
+	return "", false
+}
+
+// Rise returns how much the temperature climbed over the window ending at
+// the last point, or 0 when the points do not yet span the window.
+func Rise(points []history.Point, window time.Duration) float64 {
+	if len(points) < 2 {
+		return 0
+	}
+	last := points[len(points)-1]
+	start := last.Time.Add(-window)
+	if points[0].Time.After(start) {
+		return 0
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		if !points[i].Time.After(start) {
+			return last.Temp - points[i].Temp
+		}
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+)
+
+// hookTimeout bounds one run of the crit command, so a hung command does
+// not pile up behind later invocations.
+const hookTimeout = time.Minute
+
+// CritHook runs a shell command once a sensor has stayed at or above its
+// crit limit for Sustain, e.g. to throttle the CPU or shut down an
+// unattended machine. A single spike does not trigger it.
+type CritHook struct {
+	Command     string
+	Sustain     time.Duration
+	MinInterval time.Duration // least time between runs for one sensor
+	since       map[string]time.Time
+	last        map[string]time.Time
+}
+
+// NewCritHook creates a hook that runs command at most once per sensor
+// every 10 minutes, like the webhook.
+func NewCritHook(command string, sustain time.Duration) *CritHook {
+	return &CritHook{
+		Command:     command,
+		Sustain:     sustain,
+		MinInterval: 10 * time.Minute,
+		since:       make(map[string]time.Time),
+		last:        make(map[string]time.Time),
+	}
+}
+
+// Update feeds a poll's readings and returns the sensors whose command is
+// due: above crit since at least Sustain ago and not run within
+// MinInterval. Dropping below crit restarts the sustain timer.
+func (h *CritHook) Update(readings []sensor.Reading, t time.Time) []Event {
+	var due []Event
+	for _, r := range readings {
+		if !r.HasCrit {
+			continue
+		}
+		key := r.Key()
+		if r.Temp < r.Crit {
+			delete(h.since, key)
+			continue
+		}
+		since, ok := h.since[key]
+		if !ok {
+			h.since[key] = t
+			since = t
+		}
+		if t.Sub(since) < h.Sustain {
+			continue
+		}
+		if last, ok := h.last[key]; ok && t.Sub(last) < h.MinInterval {
+			continue
+		}
+		h.last[key] = t
+		due = append(due, Event{Key: key, Temp: r.Temp, Threshold: r.Crit, Time: t})
+	}
+	return due
+}
+
+// Run executes the command through sh -c with the sensor key and
+// temperature as $1 and $2, also set as SENSORS_KEY, SENSORS_TEMP and
+// SENSORS_CRIT. Every run is written to the event log when it starts and
+// again with its outcome, so a command that powers the machine off still
+// leaves a trace.
+func (h *CritHook) Run(e Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	temp := fmt.Sprintf("%.1f", e.Temp)
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command, "sh", e.Key, temp)
+	cmd.Env = append(os.Environ(),
+		"SENSORS_KEY="+e.Key,
+		"SENSORS_TEMP="+temp,
+		fmt.Sprintf("SENSORS_CRIT=%.1f", e.Threshold))
+
+	prefix := fmt.Sprintf("on-crit %s %s°C: %s", e.Key, temp, h.Command)
+	// The command still runs if the log cannot be written
+	startErr := store.AppendEvent(prefix+" (running)", e.Time)
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+
+	result := "ok"
+	if err != nil {
+		result = err.Error()
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+	}
+	logErr := store.AppendEvent(prefix+" ("+result+")", e.Time.Add(time.Since(start)))
+	if logErr == nil {
+		logErr = startErr
+	}
+	if logErr != nil && err == nil {
+		err = fmt.Errorf("event log: %w", logErr)
+	}
+	return err
+}
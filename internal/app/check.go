@@ -0,0 +1,127 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// Exit codes for `sensors check`, ordered by severity.
+const (
+	checkOK   = 0
+	checkHigh = 1
+	checkCrit = 2
+	checkErr  = 3
+)
+
+// runCheck reads all sensors once and reports threshold breaches through
+// the exit code: 2 if any sensor is at/above crit, 1 if any is at/above
+// high, 0 otherwise. --crit spells that out for scripts; with --crit-only
+// only crit breaches count.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Bool("crit", false, "exit 2 at/above crit, 1 at/above high, 0 otherwise, as check does without flags")
+	critOnly := fs.Bool("crit-only", false, "only fail on crit thresholds, ignore high")
+	exclude := fs.String("exclude", "", "comma-separated substrings of chip/label keys to skip")
+	source := fs.String("source", "", "only check readings from this source, e.g. smartctl")
+	if err := fs.Parse(args); err != nil {
+		return checkErr
+	}
+
+	// A failing source is a warning; the sensors that did read are still
+	// checked, unless it is the source --source asked for
+	all, readErr := sensor.ReadAll()
+	readings, err := selectReadings(all, readErr, *source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return checkErr
+	}
+	for _, e := range sensor.SourceErrors(readErr) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", e)
+	}
+
+	code, offenders := evaluateReadings(readings, splitList(*exclude), *critOnly)
+	for _, line := range offenders {
+		fmt.Println(line)
+	}
+	return code
+}
+
+// evaluateReadings returns the worst exit code across readings and one
+// description line per offending sensor.
+func evaluateReadings(readings []sensor.Reading, exclude []string, critOnly bool) (int, []string) {
+	code := checkOK
+	var offenders []string
+
+	for _, r := range readings {
+		if matchesAny(r.Key(), exclude) {
+			continue
+		}
+		switch {
+		case r.HasCrit && r.Temp >= r.Crit:
+			offenders = append(offenders, fmt.Sprintf("CRIT  %s  %.1f°C (crit %.1f)", r.Key(), r.Temp, r.Crit))
+			code = checkCrit
+		case !critOnly && r.HasHigh && r.Temp >= r.High:
+			offenders = append(offenders, fmt.Sprintf("HIGH  %s  %.1f°C (high %.1f)", r.Key(), r.Temp, r.High))
+			if code < checkHigh {
+				code = checkHigh
+			}
+		}
+	}
+	return code, offenders
+}
+
+// selectReadings picks the readings to check from a ReadAll result,
+// limited to source when it is set. It fails when nothing could be read,
+// when source is one of the readers that failed, or when source matches
+// no reading: an empty selection would otherwise pass as OK.
+func selectReadings(readings []sensor.Reading, err error, source string) ([]sensor.Reading, error) {
+	if err != nil && len(readings) == 0 {
+		return nil, err
+	}
+	if source == "" {
+		return readings, nil
+	}
+	for _, e := range sensor.SourceErrors(err) {
+		if e.Source == source {
+			return nil, e
+		}
+	}
+	readings = filterSource(readings, sensor.Source(source))
+	if len(readings) == 0 {
+		return nil, fmt.Errorf("no readings from %s", source)
+	}
+	return readings, nil
+}
+
+func filterSource(readings []sensor.Reading, src sensor.Source) []sensor.Reading {
+	var out []sensor.Reading
+	for _, r := range readings {
+		if r.Source == src {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func matchesAny(key string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
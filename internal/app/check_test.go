@@ -0,0 +1,78 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+func TestEvaluateReadings(t *testing.T) {
+	cpu := sensor.Reading{Chip: "coretemp-isa-0000", Label: "Package id 0", Temp: 85, High: 80, HasHigh: true, Crit: 100, HasCrit: true, Source: sensor.SourceLMSensors}
+	nvme := sensor.Reading{Chip: "nvme-pci-0100", Label: "Composite", Temp: 90, High: 70, HasHigh: true, Crit: 85, HasCrit: true, Source: sensor.SourceLMSensors}
+	gpu := sensor.Reading{Chip: "nvidia-gpu-0", Label: "GPU Core", Temp: 60, High: 83, HasHigh: true, Crit: 90, HasCrit: true, Source: sensor.SourceNvidia}
+	bare := sensor.Reading{Chip: "acpitz-acpi-0", Label: "temp1", Temp: 120}
+
+	tests := []struct {
+		name      string
+		readings  []sensor.Reading
+		exclude   []string
+		critOnly  bool
+		source    sensor.Source
+		want      int
+		offenders int
+	}{
+		{"all below high", []sensor.Reading{gpu}, nil, false, "", checkOK, 0},
+		{"no limits never fails", []sensor.Reading{bare}, nil, false, "", checkOK, 0},
+		{"--crit: high exits 1", []sensor.Reading{cpu, gpu}, nil, false, "", checkHigh, 1},
+		{"--crit: crit beats high", []sensor.Reading{cpu, nvme, gpu}, nil, false, "", checkCrit, 2},
+		{"--crit-only ignores high", []sensor.Reading{cpu, gpu}, nil, true, "", checkOK, 0},
+		{"--crit-only still fails on crit", []sensor.Reading{cpu, nvme}, nil, true, "", checkCrit, 1},
+		{"--exclude skips the crit sensor", []sensor.Reading{cpu, nvme}, []string{"nvme"}, false, "", checkHigh, 1},
+		{"--exclude everything hot", []sensor.Reading{cpu, nvme, gpu}, []string{"coretemp", "Composite"}, false, "", checkOK, 0},
+		{"--source leaves only the GPU", []sensor.Reading{cpu, nvme, gpu}, nil, false, sensor.SourceNvidia, checkOK, 0},
+		{"--source keeps lm-sensors", []sensor.Reading{cpu, nvme, gpu}, nil, false, sensor.SourceLMSensors, checkCrit, 2},
+	}
+	for _, tt := range tests {
+		readings := tt.readings
+		if tt.source != "" {
+			readings = filterSource(readings, tt.source)
+		}
+		code, offenders := evaluateReadings(readings, tt.exclude, tt.critOnly)
+		if code != tt.want || len(offenders) != tt.offenders {
+			t.Errorf("%s: exit %d with %q, want %d with %d offenders", tt.name, code, offenders, tt.want, tt.offenders)
+		}
+	}
+}
+
+func TestSelectReadings(t *testing.T) {
+	cpu := sensor.Reading{Chip: "coretemp-isa-0000", Label: "Package id 0", Temp: 50, Source: sensor.SourceLMSensors}
+	nvidiaErr := errors.Join(&sensor.SourceError{Source: string(sensor.SourceNvidia), Err: errors.New("exit status 9")})
+
+	tests := []struct {
+		name     string
+		readings []sensor.Reading
+		err      error
+		source   string
+		want     int // readings kept; -1 means an error
+	}{
+		{"all read", []sensor.Reading{cpu}, nil, "", 1},
+		{"a failed source is only a warning", []sensor.Reading{cpu}, nvidiaErr, "", 1},
+		{"nothing read", nil, nvidiaErr, "", -1},
+		{"--source names the failed source", []sensor.Reading{cpu}, nvidiaErr, "nvidia-smi", -1},
+		{"--source matches nothing", []sensor.Reading{cpu}, nil, "rocm-smi", -1},
+		{"--source of a working reader", []sensor.Reading{cpu}, nvidiaErr, "lm-sensors", 1},
+	}
+	for _, tt := range tests {
+		got, err := selectReadings(tt.readings, tt.err, tt.source)
+		if tt.want < 0 {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %d readings", tt.name, len(got))
+			}
+			continue
+		}
+		if err != nil || len(got) != tt.want {
+			t.Errorf("%s: got %d readings, %v, want %d", tt.name, len(got), err, tt.want)
+		}
+	}
+}
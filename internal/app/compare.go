@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/stats"
+)
+
+// runCompare prints per-sensor changes in average and peak between two
+// stored days, flagging sensors that ran significantly hotter.
+func runCompare(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	threshold := fs.Float64("threshold", 3, "flag sensors whose average rose by at least this many °C")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	locale := fs.String("locale", "", localeUsage)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Allow flags after the days, e.g. `sensors compare A B --json`
+	var days []string
+	for fs.NArg() > 0 {
+		days = append(days, fs.Arg(0))
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return 2
+		}
+	}
+	if len(days) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: sensors compare <before YYYY-MM-DD> <after YYYY-MM-DD> [--threshold N] [--json]")
+		return 2
+	}
+	setLocale(*locale)
+
+	var sums [2][]stats.Summary
+	for i, day := range days {
+		_, readings, err := loadStoredDay(day)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		sums[i] = stats.Summarize(readings)
+	}
+	deltas := stats.Compare(sums[0], sums[1], *threshold)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		out := map[string]any{"before": days[0], "after": days[1], "sensors": deltas}
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("%s → %s\n\n", days[0], days[1])
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SENSOR\tAVG\tΔAVG\tPEAK\tΔPEAK\tNOTE")
+	for _, d := range deltas {
+		switch {
+		case d.Before == nil:
+			fmt.Fprintf(tw, "%s\t%s\t\t%s\t\tnew\n", d.Key, fmtValue(d.After.Avg, d.Unit), fmtValue(d.After.Max, d.Unit))
+		case d.After == nil:
+			fmt.Fprintf(tw, "%s\t%s\t\t%s\t\tmissing\n", d.Key, fmtValue(d.Before.Avg, d.Unit), fmtValue(d.Before.Max, d.Unit))
+		default:
+			note := ""
+			if d.Hotter {
+				note = "HOTTER"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				d.Key, fmtValue(d.After.Avg, d.Unit), chart.Num("%+.1f", d.AvgDelta),
+				fmtValue(d.After.Max, d.Unit), chart.Num("%+.1f", d.MaxDelta), note)
+		}
+	}
+	tw.Flush()
+	return 0
+}
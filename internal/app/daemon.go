@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/luki/sensors/internal/daemon"
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// daemonMaxAge is how old a daemon snapshot may be before callers fall
+// back to reading sensors directly (e.g. the daemon's poll is wedged).
+const daemonMaxAge = 10 * time.Second
+
+// runDaemon polls sensors in the foreground and serves snapshots on the
+// unix socket until interrupted, and /healthz over HTTP with --http.
+func runDaemon(args []string, defaultInterval time.Duration) int {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	interval := fs.Duration("interval", defaultInterval, "poll interval")
+	socket := fs.String("socket", daemon.SocketPath(), "unix socket path")
+	httpAddr := fs.String("http", "", "also serve GET /healthz on this address, e.g. 127.0.0.1:9101")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := daemon.NewServer(*interval)
+	httpErr := make(chan error, 1)
+	if *httpAddr != "" {
+		go func() {
+			// A port already in use stops the whole daemon
+			if err := srv.ListenHTTP(ctx, *httpAddr); err != nil {
+				httpErr <- err
+				stop()
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "Serving health on http://%s/healthz\n", *httpAddr)
+	}
+	fmt.Fprintf(os.Stderr, "Serving sensor snapshots on %s every %v\n", *socket, *interval)
+	if err := srv.Serve(ctx, *socket); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	select {
+	case err := <-httpErr:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	default:
+	}
+	return 0
+}
+
+// runSnapshot prints the current readings as JSON, from the daemon when
+// it is running.
+func runSnapshot(args []string) int {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	snap, ok := daemonSnapshot()
+	if !ok {
+		readings, err := sensor.ReadAll()
+		if err != nil && len(readings) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		snap = daemon.Snapshot{Time: time.Now(), Readings: readings}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(snap); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// daemonSnapshot returns a fresh snapshot from a running daemon.
+func daemonSnapshot() (daemon.Snapshot, bool) {
+	snap, err := daemon.Query(daemon.SocketPath())
+	if err != nil || time.Since(snap.Time) > daemonMaxAge {
+		return daemon.Snapshot{}, false
+	}
+	return snap, true
+}
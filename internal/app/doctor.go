@@ -0,0 +1,58 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// runDoctor prints a per-source breakdown of sensor discovery so a missing
+// sensor can be traced to the source that dropped it.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	chips := fs.Bool("chips", false, "print the chip prefix to component map instead, [chips] entries from the config first")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *chips {
+		for _, c := range sensor.IdentityMap() {
+			fmt.Printf("%-16s  %s\n", c.Prefix, c.Name)
+		}
+		return 0
+	}
+
+	d := sensor.Diagnose()
+
+	fmt.Println("Sources:")
+	for _, s := range d.Sources {
+		status := "missing"
+		if s.Available {
+			status = "ok"
+		}
+		fmt.Printf("  %-18s  %-22s  %-7s  %3d readings", s.Name, s.Tool, status, s.Count)
+		if s.Err != nil {
+			fmt.Printf("  error: %v", s.Err)
+		}
+		fmt.Println()
+	}
+
+	for _, s := range d.Sources {
+		if s.Hint != "" {
+			fmt.Println()
+			fmt.Printf("Hint (%s): %s\n", s.Name, s.Hint)
+		}
+	}
+
+	if len(d.SkippedChips) > 0 {
+		fmt.Println()
+		fmt.Println("Skipped sensors -j chips (no temp input):")
+		for _, chip := range d.SkippedChips {
+			fmt.Printf("  %s\n", chip)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Total merged readings: %d\n", d.Total)
+	return 0
+}
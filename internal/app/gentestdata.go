@@ -0,0 +1,91 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luki/sensors/internal/demo"
+	"github.com/luki/sensors/internal/store"
+)
+
+// runGenTestdata writes synthetic day files for developing and demoing the
+// viewer without sensors. It is deliberately left out of the usage docs.
+// Existing day files are never appended to, so real history is safe.
+func runGenTestdata(args []string) int {
+	fs := flag.NewFlagSet("gen-testdata", flag.ContinueOnError)
+	days := fs.Int("days", 3, "number of days to generate")
+	end := fs.String("end", "", "last day to generate, YYYY-MM-DD (default yesterday)")
+	seed := fs.Int64("seed", 1, "random seed; the same seed gives the same data")
+	interval := fs.Duration("interval", 10*time.Second, "time between samples")
+	dataDir := fs.String("data-dir", "", "write here instead of the configured data directory")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *days <= 0 || *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --days and --interval must be positive")
+		return 2
+	}
+
+	last := time.Now().In(store.Location()).AddDate(0, 0, -1)
+	if *end != "" {
+		t, err := time.ParseInLocation("2006-01-02", *end, store.Location())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --end: %v\n", err)
+			return 2
+		}
+		last = t
+	}
+	if *dataDir != "" {
+		store.SetDataDir(*dataDir)
+	}
+
+	// A day recorded in either format counts, so check via ListDays
+	existing, err := store.ListDays("")
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, d := range existing {
+		recorded[d] = true
+	}
+
+	var dates []time.Time
+	for i := *days - 1; i >= 0; i-- {
+		day := last.AddDate(0, 0, -i)
+		if recorded[day.Format("2006-01-02")] {
+			fmt.Fprintf(os.Stderr, "Error: %s already has recorded data in %s; pick an empty --data-dir\n",
+				day.Format("2006-01-02"), store.DataDir())
+			return 1
+		}
+		dates = append(dates, day)
+	}
+
+	ds, err := store.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer ds.Close()
+
+	for _, day := range dates {
+		samples, events := demo.Day(day, *interval, *seed)
+		for _, s := range samples {
+			if err := ds.Write(s.Readings, s.Time); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+		for _, e := range events {
+			if err := store.AppendEvent(e.Label, e.Time); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+		fmt.Printf("%s  %d samples, %d stress runs\n", day.Format("2006-01-02"), len(samples), len(events)/2)
+	}
+	fmt.Printf("Wrote %d day(s) to %s\n", len(dates), store.DataDir())
+	return 0
+}
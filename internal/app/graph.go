@@ -0,0 +1,85 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/history"
+	"github.com/luki/sensors/internal/store"
+)
+
+// runGraph prints an ASCII chart of one stored sensor's day to stdout.
+func runGraph(args []string) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	chip := fs.String("chip", "", "chip ID or prefix, e.g. coretemp")
+	label := fs.String("label", "", "sensor label, e.g. \"Core 0\"")
+	day := fs.String("day", "", "day to load as YYYY-MM-DD (default: latest)")
+	width := fs.Int("width", 80, "chart width in columns")
+	height := fs.Int("height", 15, "chart height in rows")
+	since := fs.String("since", "", "start the chart at this time of day, e.g. 14:00")
+	until := fs.String("until", "", "end the chart at this time of day, e.g. 16:00")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *chip == "" || *label == "" {
+		fmt.Fprintln(os.Stderr, "Usage: sensors graph --chip <chip> --label <label> [--day YYYY-MM-DD] [--since HH:MM] [--until HH:MM] [--width N] [--height N]")
+		return 2
+	}
+	window, err := store.ParseClockWindow(*since, *until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if *day == "" {
+		days, err := store.ListDays("")
+		if err != nil || len(days) == 0 {
+			fmt.Fprintf(os.Stderr, "No history data found in %s\n", store.DataDir())
+			return 1
+		}
+		*day = days[0]
+	}
+
+	readings, err := store.LoadDay(*day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Group matches by chip so a prefix matching several chips picks one
+	// deterministically
+	byChip := make(map[string][]history.Point)
+	for _, r := range window.Filter(readings) {
+		if r.Label == *label && strings.HasPrefix(r.Chip, *chip) {
+			byChip[r.Chip] = append(byChip[r.Chip], history.Point{Temp: r.Temp, Time: r.Time})
+		}
+	}
+	if len(byChip) == 0 {
+		where := *day
+		if !window.IsZero() {
+			where += " " + window.String()
+		}
+		fmt.Fprintf(os.Stderr, "No readings for %s/%s on %s\n", *chip, *label, where)
+		return 1
+	}
+	chips := make([]string, 0, len(byChip))
+	for c := range byChip {
+		chips = append(chips, c)
+	}
+	sort.Strings(chips)
+
+	pts := byChip[chips[0]]
+	sort.Slice(pts, func(i, j int) bool { return pts[i].Time.Before(pts[j].Time) })
+
+	heading := *day
+	if !window.IsZero() {
+		heading += " " + window.String()
+	}
+	fmt.Printf("%s/%s  %s\n\n", chips[0], *label, heading)
+	fmt.Print(chart.RenderASCII(pts, *width, *height))
+	return 0
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/luki/sensors/internal/config"
+	"github.com/luki/sensors/internal/store"
+	"github.com/luki/sensors/internal/viewer"
+)
+
+// runHistory opens the history viewer, optionally limited to sensors
+// matching --only, or on a single CSV with --file.
+func runHistory(args []string, cfg config.Config) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	only := fs.String("only", "", "comma-separated substrings of chip/label keys to show, e.g. coretemp,nvme")
+	file := fs.String("file", "", "open this CSV file instead of the data directory")
+	locale := fs.String("locale", "", localeUsage)
+	since := fs.String("since", "", "only load readings from this time of day, e.g. 14:00")
+	until := fs.String("until", "", "only load readings up to this time of day, e.g. 16:00")
+	fresh := fs.Bool("no-resume", false, "open at the latest day instead of where the last session was left")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setLocale(*locale)
+	window, err := store.ParseClockWindow(*since, *until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	err = viewer.Run(viewer.Options{
+		MaxGap: cfg.MaxGap,
+		Window: cfg.HistoryWindow,
+		Only:   splitList(*only),
+		File:   *file,
+		Clock:  window,
+		Fresh:  *fresh,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
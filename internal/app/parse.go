@@ -0,0 +1,66 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// runParse runs a captured `sensors -j` dump through the lm-sensors parser
+// and prints the readings it yields, so a user's report can be reproduced
+// without their hardware.
+func runParse(args []string) int {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	jsonPath := fs.String("json", "", "`sensors -j` output to parse; - reads stdin")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *jsonPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --json FILE is required")
+		return 2
+	}
+
+	var raw []byte
+	var err error
+	if *jsonPath == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(*jsonPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	readings, err := sensor.ParseSensorsJSON(raw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *jsonPath, err)
+		return 1
+	}
+	if len(readings) == 0 {
+		fmt.Println("No temperature readings")
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tSENSOR\tADAPTER\tVALUE\tHIGH\tCRIT")
+	for _, r := range readings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			sensor.FriendlyName(r.Chip), r.Key(), r.Adapter, fmtValue(r.Temp, r.Unit),
+			fmtLimit(r.High, r.HasHigh), fmtLimit(r.Crit, r.HasCrit))
+	}
+	tw.Flush()
+	return 0
+}
+
+// fmtLimit formats a threshold, "-" when the hardware reports none.
+func fmtLimit(v float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return chart.Num("%.1f", v)
+}
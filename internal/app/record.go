@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luki/sensors/internal/alert"
+	"github.com/luki/sensors/internal/config"
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+)
+
+// sizeCapEvery is how often the recorder checks --max-size.
+const sizeCapEvery = 10 * time.Minute
+
+// runRecord polls sensors without the TUI and appends every reading to
+// the daily CSV, or to --out. With --only and a sub-second --interval it
+// logs a single sensor at high rate to catch transient spikes.
+func runRecord(args []string, cfg config.Config) int {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	only := fs.String("only", "", "comma-separated substrings of chip/label keys to record, e.g. \"coretemp/Package id 0\"")
+	interval := fs.Duration("interval", cfg.Interval, "poll interval, e.g. 100ms")
+	out := fs.String("out", "", "write to this file instead of the daily data directory; .jsonl writes JSON Lines")
+	storeFmt := fs.String("store", "", "day file format: csv or jsonl (default from the config)")
+	webhook := fs.String("webhook", cfg.Webhook, "POST a JSON alert to this URL when a sensor crosses crit")
+	onCrit := fs.String("on-crit", cfg.OnCrit, onCritUsage)
+	sustain := fs.Duration("crit-sustain", cfg.CritSustain, "how long a sensor must stay above crit before --on-crit runs")
+	maxSize := fs.String("max-size", "", "delete the oldest days when the data directory grows past this size, e.g. 500M")
+	dryRun := fs.Bool("dry-run", false, "with --max-size, list the days that would be deleted and exit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	var sizeCap int64
+	if *maxSize != "" {
+		if *out != "" {
+			fmt.Fprintln(os.Stderr, "Error: --max-size applies to the data directory, not --out")
+			return 2
+		}
+		var err error
+		if sizeCap, err = store.ParseSize(*maxSize); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --max-size: %v\n", err)
+			return 2
+		}
+	}
+	if *dryRun {
+		if *maxSize == "" {
+			fmt.Fprintln(os.Stderr, "Error: --dry-run needs --max-size")
+			return 2
+		}
+		return printSizeCapPlan(sizeCap)
+	}
+	if *storeFmt != "" {
+		if err := store.SetFormat(*storeFmt); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		return 2
+	}
+
+	var ds *store.DiskStore
+	var err error
+	dest := *out
+	if dest != "" {
+		ds, err = store.NewFile(dest)
+	} else {
+		ds, err = store.New()
+		dest = store.DataDir()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer ds.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var detector *alert.Detector
+	var notify *alert.Webhook
+	if *webhook != "" {
+		detector = alert.NewDetector()
+		notify = alert.NewWebhook(*webhook)
+	}
+	var hook *alert.CritHook
+	if *onCrit != "" {
+		hook = alert.NewCritHook(*onCrit, *sustain)
+	}
+
+	patterns := splitList(*only)
+	fmt.Fprintf(os.Stderr, "Recording to %s every %v (Ctrl+C to stop)\n", dest, *interval)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	var capChecked time.Time
+	var lastWarn string
+	samples := 0
+	done := func() int {
+		fmt.Fprintf(os.Stderr, "Recorded %d samples\n", samples)
+		return 0
+	}
+	for {
+		readings, err := sensor.ReadAllContext(ctx)
+		if ctx.Err() != nil {
+			return done()
+		}
+		if err != nil && len(readings) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		// Keep recording the sources that work, warning when the set of
+		// failures changes rather than on every poll
+		if msg := errString(err); msg != lastWarn {
+			if msg != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", strings.ReplaceAll(msg, "\n", "; "))
+			}
+			lastWarn = msg
+		}
+
+		if len(patterns) > 0 {
+			readings = filterKeys(readings, patterns)
+			if samples == 0 && len(readings) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: no sensors match %q\n", *only)
+				return 1
+			}
+		}
+		now := time.Now()
+		if err := ds.Write(readings, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		samples++
+		if sizeCap > 0 && now.Sub(capChecked) >= sizeCapEvery {
+			capChecked = now
+			removed, err := store.EnforceSizeCap(sizeCap)
+			if len(removed) > 0 {
+				fmt.Fprintf(os.Stderr, "Data directory over %s, deleted %s\n", *maxSize, strings.Join(removed, ", "))
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: enforcing --max-size: %v\n", err)
+			}
+		}
+		if detector != nil {
+			for _, e := range detector.Update(readings, now) {
+				if !notify.Allow(e) {
+					continue
+				}
+				// In the background, so a slow endpoint does not stall polling
+				go func() {
+					if err := notify.Send(e); err != nil {
+						fmt.Fprintf(os.Stderr, "webhook for %s: %v\n", e.Key, err)
+					}
+				}()
+			}
+		}
+		if hook != nil {
+			for _, e := range hook.Update(readings, now) {
+				fmt.Fprintf(os.Stderr, "%s above crit for %v, running --on-crit\n", e.Key, *sustain)
+				// In the background, so recording goes on while it runs
+				go func() {
+					if err := hook.Run(e); err != nil {
+						fmt.Fprintf(os.Stderr, "on-crit for %s: %v\n", e.Key, err)
+					}
+				}()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return done()
+		case <-ticker.C:
+		}
+	}
+}
+
+// errString is err's message, or "" for nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// printSizeCapPlan reports which days --max-size would delete now.
+func printSizeCapPlan(maxBytes int64) int {
+	days, total, err := store.PlanSizeCap(maxBytes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%s holds %.1f MiB, cap %.1f MiB\n", store.DataDir(), float64(total)/(1<<20), float64(maxBytes)/(1<<20))
+	if len(days) == 0 {
+		fmt.Println("Nothing to delete")
+		return 0
+	}
+	for _, day := range days {
+		fmt.Printf("would delete %s\n", day)
+	}
+	return 0
+}
+
+// filterKeys keeps readings whose chip/label key matches any pattern.
+func filterKeys(readings []sensor.Reading, patterns []string) []sensor.Reading {
+	var out []sensor.Reading
+	for _, r := range readings {
+		if matchesAny(r.Key(), patterns) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
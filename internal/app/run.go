@@ -1,37 +1,176 @@
 package app
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/config"
 	"github.com/luki/sensors/internal/monitor"
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
 	"github.com/luki/sensors/internal/stress"
-	"github.com/luki/sensors/internal/viewer"
 )
 
-// Run dispatches CLI arguments to the monitor, history viewer, or stress runner.
+// Run dispatches CLI arguments to the monitor, history viewer, stress runner,
+// or one-shot commands, returning the process exit code.
 func Run(args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	store.SetDataDir(cfg.DataDir)
+	loc, _ := cfg.Location() // validated by Load
+	store.SetLocation(loc)
+	store.SetMillis(cfg.Millis)
+	if err := store.SetFormat(cfg.Store); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	thresholds := make(map[string]sensor.Thresholds, len(cfg.Thresholds))
+	for name, t := range cfg.Thresholds {
+		thresholds[name] = sensor.Thresholds{High: t.High, Crit: t.Crit}
+	}
+	sensor.SetChipNames(cfg.Chips)
+	sensor.SetComponentThresholds(thresholds)
+	ranges := make(map[string]chart.FixedRange, len(cfg.Ranges))
+	for name, r := range cfg.Ranges {
+		ranges[name] = chart.FixedRange{Min: r.Min, Max: r.Max}
+	}
+	chart.SetFixedRanges(ranges)
+	setLocale("")
+	chart.SetTheme(chart.Theme{
+		WarmFraction: cfg.Theme.WarmFraction,
+		Ok:           lipgloss.Color(cfg.Theme.Ok),
+		Warm:         lipgloss.Color(cfg.Theme.Warm),
+		High:         lipgloss.Color(cfg.Theme.High),
+		Crit:         lipgloss.Color(cfg.Theme.Crit),
+	})
+
 	switch {
-	case len(args) > 0 && args[0] == "--history":
-		viewer.Run()
-		return 0
+	case len(args) > 0 && (args[0] == "history" || args[0] == "--history"):
+		return runHistory(args[1:], cfg)
 
 	case len(args) > 0 && args[0] == "stress":
-		stress.Run(args[1:])
+		stress.Run(args[1:], cfg.StressDuration)
 		return 0
 
+	case len(args) > 0 && args[0] == "check":
+		return runCheck(args[1:])
+
+	case len(args) > 0 && args[0] == "doctor":
+		return runDoctor(args[1:])
+
+	case len(args) > 0 && args[0] == "graph":
+		return runGraph(args[1:])
+
+	case len(args) > 0 && args[0] == "record":
+		return runRecord(args[1:], cfg)
+
+	case len(args) > 0 && args[0] == "stats":
+		return runStats(args[1:])
+
+	case len(args) > 0 && args[0] == "compare":
+		return runCompare(args[1:])
+
+	case len(args) > 0 && args[0] == "status":
+		return runStatus(args[1:])
+
+	case len(args) > 0 && args[0] == "snapshot":
+		return runSnapshot(args[1:])
+
+	case len(args) > 0 && args[0] == "daemon":
+		return runDaemon(args[1:], cfg.Interval)
+
+	case len(args) > 0 && args[0] == "parse":
+		return runParse(args[1:])
+
+	case len(args) > 0 && args[0] == "gen-testdata":
+		return runGenTestdata(args[1:])
+
 	default:
+		fs := flag.NewFlagSet("sensors", flag.ContinueOnError)
+		interval := fs.Duration("interval", cfg.Interval, "poll interval")
+		historySize := fs.Int("history-size", cfg.HistorySize, "points kept per sensor")
+		expirePolls := fs.Int("expire-polls", cfg.ExpirePolls, "drop a sensor and its history after this many polls without a reading")
+		dataDir := fs.String("data-dir", cfg.DataDir, "CSV data directory (default ~/.sensors-data)")
+		storeFmt := fs.String("store", cfg.Store, "day file format: csv or jsonl")
+		webhook := fs.String("webhook", cfg.Webhook, "POST a JSON alert to this URL when a sensor crosses crit")
+		onCrit := fs.String("on-crit", cfg.OnCrit, onCritUsage)
+		sustain := fs.Duration("crit-sustain", cfg.CritSustain, "how long a sensor must stay above crit before --on-crit runs")
+		outlier := fs.Float64("outlier-delta", cfg.OutlierDelta, "ignore readings this many °C off the recent average in min/peak/avg (0 disables)")
+		cohort := fs.Float64("cohort-sigma", cfg.CohortSigma, "mark a temperature this many standard deviations above its like-named siblings, e.g. one core against the others (0 disables)")
+		compact := fs.Bool("compact", false, "start with one line per sensor and no charts (toggle with v)")
+		cpuLoad := fs.Bool("cpu-load", false, "also show CPU utilization from /proc/stat (Linux)")
+		locale := fs.String("locale", "", localeUsage)
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		setLocale(*locale)
+		store.SetDataDir(*dataDir)
+		if err := store.SetFormat(*storeFmt); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if *cpuLoad {
+			sensor.EnableCPULoad()
+		}
+
 		p := tea.NewProgram(
-			monitor.New(),
+			monitor.New(monitor.Options{
+				Interval:     *interval,
+				HistorySize:  *historySize,
+				ExpirePolls:  *expirePolls,
+				Webhook:      *webhook,
+				OnCrit:       *onCrit,
+				CritSustain:  *sustain,
+				OutlierDelta: *outlier,
+				CohortSigma:  *cohort,
+				Compact:      *compact,
+				Hidden:       cfg.Hidden,
+				SaveHidden:   config.SaveHidden,
+				Pinned:       cfg.Pinned,
+				SavePinned:   config.SavePinned,
+			}),
 			tea.WithAltScreen(),
 			tea.WithMouseCellMotion(),
 		)
-		if _, err := p.Run(); err != nil {
+		final, err := p.Run()
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
+		// Printed after the alt screen is gone so it stays in the scrollback
+		if m, ok := final.(monitor.Model); ok {
+			fmt.Print(m.Summary(time.Now()))
+			if err := m.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
 		return 0
 	}
 }
+
+const onCritUsage = "run this shell command, with the sensor key and temperature as $1 and $2, when a sensor stays above crit for --crit-sustain"
+
+const localeUsage = "format numbers for this locale, e.g. de_DE for a decimal comma (default from LC_ALL or LC_NUMERIC)"
+
+// setLocale picks the decimal separator for displayed numbers from a
+// --locale value, or from LC_ALL then LC_NUMERIC when it is empty. LANG
+// alone does not switch it, so output only changes for users who asked
+// for locale-specific numbers.
+func setLocale(name string) {
+	if name == "" {
+		name = os.Getenv("LC_ALL")
+	}
+	if name == "" {
+		name = os.Getenv("LC_NUMERIC")
+	}
+	chart.SetDecimalComma(chart.DecimalComma(name))
+}
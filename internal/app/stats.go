@@ -0,0 +1,135 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/stats"
+	"github.com/luki/sensors/internal/store"
+)
+
+// runStats prints per-sensor summary statistics for a stored day, as an
+// aligned table or JSON.
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	locale := fs.String("locale", "", localeUsage)
+	since := fs.String("since", "", "only use readings from this time of day, e.g. 14:00")
+	until := fs.String("until", "", "only use readings up to this time of day, e.g. 16:00")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Allow flags after the day, e.g. `sensors stats 2026-02-21 --json`
+	day := ""
+	if fs.NArg() > 0 {
+		day = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return 2
+		}
+	}
+
+	setLocale(*locale)
+	window, err := store.ParseClockWindow(*since, *until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	day, readings, err := loadStoredDay(day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	readings = window.Filter(readings)
+	if !window.IsZero() && len(readings) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no readings between %s on %s\n", window, day)
+		return 1
+	}
+	sums := stats.Summarize(readings)
+
+	if *asJSON {
+		// day stays a plain date; the window goes in fields of its own
+		out := map[string]any{"day": day, "sensors": sums}
+		if *since != "" {
+			out["since"] = *since
+		}
+		if *until != "" {
+			out["until"] = *until
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if !window.IsZero() {
+		day += " " + window.String()
+	}
+	fmt.Printf("Stats for %s\n\n", day)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SENSOR\tSAMPLES\tMIN\tAVG\tMAX\tP95\t>HIGH\t>CRIT\tGAPS")
+	for _, s := range sums {
+		gaps := fmt.Sprintf("%d", s.Gaps)
+		if s.Gaps > 0 {
+			gaps += " (max " + fmtSpan(s.LongestGap) + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Key, s.Samples,
+			fmtValue(s.Min, s.Unit), fmtValue(s.Avg, s.Unit), fmtValue(s.Max, s.Unit), fmtValue(s.P95, s.Unit),
+			fmtAbove(s.AboveHigh, s.High), fmtAbove(s.AboveCrit, s.Crit), gaps)
+	}
+	tw.Flush()
+	return 0
+}
+
+// loadStoredDay loads a day's readings with duplicate rows removed,
+// defaulting to the latest day when day is empty.
+func loadStoredDay(day string) (string, []store.StoredReading, error) {
+	if day == "" {
+		days, err := store.ListDays("")
+		if err != nil || len(days) == 0 {
+			return "", nil, fmt.Errorf("no history data found in %s", store.DataDir())
+		}
+		day = days[0]
+	}
+	readings, err := store.LoadDay(day)
+	if err != nil {
+		return "", nil, err
+	}
+	return day, store.Dedup(readings), nil
+}
+
+// fmtValue formats a reading value with its unit, °C when unset.
+func fmtValue(v float64, unit string) string {
+	if unit == "" {
+		unit = "°C"
+	}
+	return chart.Num("%.1f", v) + unit
+}
+
+// fmtAbove formats time spent above a threshold, "-" when there is none.
+func fmtAbove(d time.Duration, threshold float64) string {
+	if threshold <= 0 {
+		return "-"
+	}
+	return fmtSpan(d)
+}
+
+func fmtSpan(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	}
+	if d >= time.Minute {
+		return fmt.Sprintf("%dm%02ds", d/time.Minute, (d%time.Minute)/time.Second)
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// runStatus prints a single line with the hottest temperature per
+// component, e.g. "CPU 62° GPU 71° NVMe 44°", for shell prompts and tmux
+// status bars. Readings come from `sensors daemon` when it is running, and
+// are otherwise cached briefly so frequent calls stay cheap.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	color := fs.Bool("color", false, "colorize temperatures with ANSI escapes")
+	groups := fs.String("groups", "", "comma-separated components to show, e.g. CPU,GPU,NVMe (default: all)")
+	maxAge := fs.Duration("max-age", 2*time.Second, "reuse cached readings younger than this; 0 disables the cache")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	readings, err := cachedReadings(*maxAge)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *color {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+	fmt.Println(formatStatus(hottestByGroup(readings, splitList(*groups)), *color))
+	return 0
+}
+
+// statusGroup is the hottest temperature reading of one component.
+type statusGroup struct {
+	name    string
+	reading sensor.Reading
+}
+
+// statusOrder puts the components people usually watch first; others
+// follow in order of appearance.
+var statusOrder = []string{"CPU", "GPU", "NVMe", "HDD/SSD"}
+
+// hottestByGroup keeps the hottest temperature reading per component,
+// ordered as listed in only, or by statusOrder when only is empty.
+// Components are the first word of the friendly name, so AMD and NVIDIA
+// GPUs share "GPU".
+func hottestByGroup(readings []sensor.Reading, only []string) []statusGroup {
+	var out []statusGroup
+	index := make(map[string]int)
+	for _, r := range readings {
+		if r.Unit != "" {
+			continue
+		}
+		name := "Sensor"
+		if f := strings.Fields(sensor.FriendlyName(r.Chip)); len(f) > 0 {
+			name = f[0]
+		}
+		if len(only) > 0 && !containsFold(only, name) {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			index[name] = len(out)
+			out = append(out, statusGroup{name: name, reading: r})
+			continue
+		}
+		if r.Temp > out[i].reading.Temp {
+			out[i].reading = r
+		}
+	}
+
+	order := statusOrder
+	if len(only) > 0 {
+		order = only
+	}
+	rank := func(name string) int {
+		for i, o := range order {
+			if strings.EqualFold(o, name) {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank(out[i].name) < rank(out[j].name) })
+	return out
+}
+
+func formatStatus(groups []statusGroup, color bool) string {
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		r := g.reading
+		temp := fmt.Sprintf("%.0f°", r.Temp)
+		if color {
+			temp = lipgloss.NewStyle().
+				Foreground(chart.TempColor(r.Temp, r.High, r.Crit, r.HasHigh, r.HasCrit)).
+				Render(temp)
+		}
+		parts = append(parts, g.name+" "+temp)
+	}
+	return strings.Join(parts, " ")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusCachePath returns a per-user cache file in the runtime directory,
+// falling back to the system temp directory.
+func statusCachePath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("sensors-status-%d.json", os.Getuid()))
+}
+
+// cachedReadings returns the daemon's snapshot if one is running, then
+// readings from the cache file when it is younger than maxAge, and
+// otherwise reads all sensors and refreshes the cache.
+func cachedReadings(maxAge time.Duration) ([]sensor.Reading, error) {
+	if snap, ok := daemonSnapshot(); ok {
+		return snap.Readings, nil
+	}
+
+	path := statusCachePath()
+	if maxAge > 0 {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < maxAge {
+			if data, err := os.ReadFile(path); err == nil {
+				var readings []sensor.Reading
+				if json.Unmarshal(data, &readings) == nil {
+					return readings, nil
+				}
+			}
+		}
+	}
+
+	// A prompt is better served by the sources that work than by nothing
+	readings, err := sensor.ReadAll()
+	if err != nil && len(readings) == 0 {
+		return nil, err
+	}
+	if maxAge > 0 {
+		if data, err := json.Marshal(readings); err == nil {
+			// Write-then-rename so a concurrent prompt never reads a partial file
+			tmp := path + ".tmp"
+			if os.WriteFile(tmp, data, 0600) == nil {
+				os.Rename(tmp, path)
+			}
+		}
+	}
+	return readings, nil
+}
@@ -0,0 +1,101 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/luki/sensors/internal/history"
+)
+
+// RenderASCII renders a multi-line plain-text chart (no ANSI escapes)
+// suitable for pasting into issues. Points are bucketed by time into width
+// columns, each column plotting the bucket average, with a labeled Y axis
+// and start/middle/end times underneath.
+func RenderASCII(points []history.Point, width, height int) string {
+	if len(points) == 0 || width <= 0 || height <= 1 {
+		return ""
+	}
+
+	cols := bucketAverages(points, width)
+
+	lo, hi := math.MaxFloat64, -math.MaxFloat64
+	for _, v := range cols {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi-lo < 1 {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	span := hi - lo
+
+	grid := make([][]byte, height)
+	for row := range grid {
+		grid[row] = []byte(strings.Repeat(" ", width))
+	}
+	for col, v := range cols {
+		if math.IsNaN(v) {
+			continue
+		}
+		row := height - 1 - int(math.Round((v-lo)/span*float64(height-1)))
+		grid[row][col] = '*'
+	}
+
+	var sb strings.Builder
+	for row := 0; row < height; row++ {
+		label := ""
+		if row == 0 || row == height/2 || row == height-1 {
+			label = Num("%6.1f", hi-float64(row)/float64(height-1)*span)
+		}
+		fmt.Fprintf(&sb, "%6s |%s\n", label, strings.TrimRight(string(grid[row]), " "))
+	}
+	fmt.Fprintf(&sb, "%6s +%s\n", "", strings.Repeat("-", width))
+
+	first := points[0].Time.Format("15:04")
+	mid := points[len(points)/2].Time.Format("15:04")
+	last := points[len(points)-1].Time.Format("15:04")
+	axis := []byte(strings.Repeat(" ", width))
+	copy(axis, first)
+	if width > 2*len(mid)+len(first) {
+		copy(axis[width/2-len(mid)/2:], mid)
+	}
+	if width >= len(first)+len(last)+1 {
+		copy(axis[width-len(last):], last)
+	}
+	fmt.Fprintf(&sb, "%6s  %s\n", "", strings.TrimRight(string(axis), " "))
+
+	return sb.String()
+}
+
+// bucketAverages splits points evenly by time into n buckets and returns
+// the average temperature of each; empty buckets are NaN.
+func bucketAverages(points []history.Point, n int) []float64 {
+	sums := make([]float64, n)
+	counts := make([]int, n)
+
+	start := points[0].Time
+	dur := points[len(points)-1].Time.Sub(start)
+	for i, p := range points {
+		var col int
+		if dur > 0 {
+			col = int(float64(p.Time.Sub(start)) / float64(dur) * float64(n-1))
+		} else if len(points) > 1 {
+			col = i * (n - 1) / (len(points) - 1)
+		}
+		sums[col] += p.Temp
+		counts[col]++
+	}
+
+	out := make([]float64, n)
+	for i := range out {
+		if counts[i] == 0 {
+			out[i] = math.NaN()
+		} else {
+			out[i] = sums[i] / float64(counts[i])
+		}
+	}
+	return out
+}
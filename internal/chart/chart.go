@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -14,18 +15,76 @@ import (
 
 var sparkBlocks = []rune{'\u2581', '\u2582', '\u2583', '\u2584', '\u2585', '\u2586', '\u2587', '\u2588'}
 
-// TempColor returns the appropriate color for a temperature value given thresholds.
+// critWarnFraction is the fraction of crit where the warm band starts for
+// sensors that report crit but no high limit.
+const critWarnFraction = 0.9
+
+// TempColor returns the active theme's color for a temperature value
+// given thresholds. A sensor with only a crit limit still gets a warm band
+// below it, starting at critWarnFraction of crit.
 func TempColor(v, high, crit float64, hasHigh, hasCrit bool) lipgloss.Color {
 	switch {
 	case hasCrit && v >= crit:
-		return lipgloss.Color("196") // red
+		return theme.Crit
 	case hasHigh && v >= high:
-		return lipgloss.Color("208") // orange
-	case hasHigh && v >= high*0.85:
-		return lipgloss.Color("220") // yellow
+		return theme.High
+	case hasHigh && v >= high*theme.WarmFraction:
+		return theme.Warm
+	case !hasHigh && hasCrit && v >= crit*critWarnFraction:
+		return theme.Warm
 	default:
-		return lipgloss.Color("78") // soft green
+		return theme.Ok
+	}
+}
+
+// tempPad is the headroom, in °C, Range leaves around temperatures.
+const tempPad = 5
+
+// Range returns the vertical range for charting values between lo and hi,
+// padded so the extremes do not sit on the chart's edges. Temperatures
+// (unit "") are padded by tempPad and may go below zero, for outdoor and
+// chiller sensors. Other units (W, RPM, %) are never negative, so their
+// padding scales with the values and the floor stays at zero.
+func Range(lo, hi float64, unit string) (rangeMin, rangeMax float64) {
+	if unit == "" {
+		return lo - tempPad, hi + tempPad
+	}
+	pad := math.Max((hi-lo)/10, math.Abs(hi)/20)
+	if pad == 0 {
+		pad = 1
+	}
+	return math.Max(0, lo-pad), hi + pad
+}
+
+// FixedRange is a chart range pinned for a component's temperatures, so a
+// value is drawn at the same height however the data moves.
+type FixedRange struct {
+	Min, Max float64
+}
+
+var fixedRanges map[string]FixedRange
+
+// SetFixedRanges pins the chart range of temperatures per component, keyed
+// by FriendlyName such as "CPU". Components without an entry auto-range.
+func SetFixedRanges(ranges map[string]FixedRange) {
+	fixedRanges = ranges
+}
+
+// SensorRange returns the chart range for one sensor of a component: the
+// component's fixed range when configured, otherwise Range over lo..hi
+// widened to show its high and crit limits.
+func SensorRange(component, unit string, lo, hi, high, crit float64, hasHigh, hasCrit bool) (rangeMin, rangeMax float64) {
+	if fr, ok := fixedRanges[component]; ok && unit == "" {
+		return fr.Min, fr.Max
 	}
+	rangeMin, rangeMax = Range(lo, hi, unit)
+	if hasCrit && crit > rangeMax {
+		rangeMax = crit + tempPad
+	}
+	if hasHigh && high > rangeMax {
+		rangeMax = high + tempPad
+	}
+	return rangeMin, rangeMax
 }
 
 // RenderSparkline renders a sparkline chart with color-coded blocks.
@@ -44,12 +103,26 @@ func RenderSparkline(values []float64, width int, rangeMin, rangeMax float64, hi
 // RenderSparklinePoints renders a sparkline with minute tick marks on the
 // timeline. A subtle pipe is drawn at each minute boundary.
 func RenderSparklinePoints(points []history.Point, width int, rangeMin, rangeMax float64, high, crit float64, hasHigh, hasCrit bool) string {
+	return RenderSparklineMarked(points, nil, width, rangeMin, rangeMax, high, crit, hasHigh, hasCrit)
+}
+
+// MarkerColor is the color of event markers on sparklines and scrubbers.
+var MarkerColor = lipgloss.Color("213")
+
+// SuspectColor draws outlier readings (history.Point.Suspect) so a glitch
+// stays visible without looking like a real crit spike.
+var SuspectColor = lipgloss.Color("240")
+
+// RenderSparklineMarked is RenderSparklinePoints with event markers: a
+// column whose time span contains a marker time is drawn as a colored bar,
+// taking precedence over minute ticks.
+func RenderSparklineMarked(points []history.Point, markers []time.Time, width int, rangeMin, rangeMax float64, high, crit float64, hasHigh, hasCrit bool) string {
 	if width <= 0 {
 		return ""
 	}
 
 	if len(points) == 0 {
-		dim := lipgloss.NewStyle().Foreground(lipgloss.Color("236"))
+		dim := lipgloss.NewStyle().Foreground(DimGray("236"))
 		return dim.Render(strings.Repeat("\u254C", width))
 	}
 
@@ -64,15 +137,36 @@ func RenderSparklinePoints(points []history.Point, width int, rangeMin, rangeMax
 	}
 
 	var sb strings.Builder
+	sb.Grow(width * 16) // a block plus its color escapes
 
-	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("236"))
-	for i := 0; i < padLen; i++ {
-		sb.WriteString(dim.Render("\u254C"))
+	if padLen > 0 {
+		dim := lipgloss.NewStyle().Foreground(DimGray("236"))
+		sb.WriteString(dim.Render(strings.Repeat("\u254C", padLen)))
 	}
 
-	tickStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("239"))
+	// Adjacent cells of the same style share one pair of escapes, which
+	// keeps the output small on slow links; ticks and markers have their
+	// own colors, so they still break runs.
+	styles := make(spanStyles, 8)
+	var cur spanKey
+	inRun := false
+	write := func(k spanKey, ch rune) {
+		if !inRun || k != cur {
+			if inRun {
+				sb.WriteString(styles.get(cur).close)
+			}
+			sb.WriteString(styles.get(k).open)
+			cur, inRun = k, true
+		}
+		sb.WriteRune(ch)
+	}
 
 	for i, p := range points {
+		if hasMarker(points, i, markers) {
+			write(spanKey{MarkerColor, true}, '\u2503')
+			continue
+		}
+
 		norm := (p.Temp - rangeMin) / span
 		norm = math.Max(0, math.Min(1, norm))
 
@@ -93,21 +187,222 @@ func RenderSparklinePoints(points []history.Point, width int, rangeMin, rangeMax
 		}
 
 		if isMinuteTick {
-			sb.WriteString(tickStyle.Render("\u2502"))
+			write(spanKey{lipgloss.Color("239"), false}, '\u2502')
 		} else {
-			ch := string(sparkBlocks[idx])
 			color := TempColor(p.Temp, high, crit, hasHigh, hasCrit)
-			style := lipgloss.NewStyle().Foreground(color)
-			if hasCrit && p.Temp >= crit {
-				style = style.Bold(true)
+			if p.Suspect {
+				color = SuspectColor
 			}
-			sb.WriteString(style.Render(ch))
+			bold := hasCrit && p.Temp >= crit && !p.Suspect
+			write(spanKey{color, bold}, sparkBlocks[idx])
 		}
 	}
+	if inRun {
+		sb.WriteString(styles.get(cur).close)
+	}
 
 	return sb.String()
 }
 
+// Collecting reports whether points are too few to chart: none, one, or
+// two of the same value, as in the first seconds after startup.
+func Collecting(points []history.Point) bool {
+	switch len(points) {
+	case 0, 1:
+		return true
+	case 2:
+		return points[0].Temp == points[1].Temp
+	}
+	return false
+}
+
+// RenderCollecting fills a sparkline's width with a dim "collecting…"
+// instead of a lone block at the right edge.
+func RenderCollecting(width int) string {
+	if width <= 0 {
+		return ""
+	}
+	text := []rune("collecting\u2026")
+	if len(text) > width {
+		text = text[:width]
+	}
+	dim := lipgloss.NewStyle().Foreground(DimGray("236"))
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true).Render(string(text)) +
+		dim.Render(strings.Repeat("\u254C", width-len(text)))
+}
+
+// spanStyles caches, per color and weight, the escape sequences lipgloss
+// wraps text in, so a chart renders each style once and can wrap whole
+// runs of cells in a single pair of escapes.
+type spanStyles map[spanKey]spanStyle
+
+type spanKey struct {
+	color lipgloss.Color
+	bold  bool
+}
+
+type spanStyle struct{ open, close string }
+
+func (c spanStyles) get(k spanKey) spanStyle {
+	st, ok := c[k]
+	if !ok {
+		// Render a placeholder and keep what surrounds it; "x" never
+		// appears inside an SGR sequence
+		open, close, _ := strings.Cut(lipgloss.NewStyle().Foreground(k.color).Bold(k.bold).Render("x"), "x")
+		st = spanStyle{open, close}
+		c[k] = st
+	}
+	return st
+}
+
+// EnvelopeColor is the dim color of the peak-hold envelope.
+var EnvelopeColor = lipgloss.Color("240")
+
+// RenderEnvelope renders values (oldest first) as a dim, right-aligned
+// sparkline on the same scale as RenderSparklinePoints, e.g. to show the
+// peak-hold envelope above the live chart.
+func RenderEnvelope(values []float64, width int, rangeMin, rangeMax float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	span := rangeMax - rangeMin
+	if span <= 0 {
+		span = 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		norm := math.Max(0, math.Min(1, (v-rangeMin)/span))
+		idx := int(norm * 7)
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return lipgloss.NewStyle().Foreground(EnvelopeColor).Render(sb.String())
+}
+
+// Threshold line colors for RenderTall, matching the H and C tags.
+var (
+	HighLineColor = lipgloss.Color("220")
+	CritLineColor = lipgloss.Color("196")
+)
+
+// WatermarkColor draws the peak line of RenderWatermark, dim so it reads as
+// a guide rather than a limit.
+var WatermarkColor = lipgloss.Color("240")
+
+// RenderTall renders the newest points as a bar chart height rows tall,
+// top row first, on the same scale as RenderSparklinePoints but with eight
+// levels per row. The high and crit limits are drawn as horizontal rules
+// behind the bars, so the distance to them can be judged at a glance.
+func RenderTall(points []history.Point, width, height int, rangeMin, rangeMax, high, crit float64, hasHigh, hasCrit bool) []string {
+	rules := map[int]lipgloss.Color{}
+	if row := ruleRow(high, hasHigh, height, rangeMin, rangeMax); row >= 0 {
+		rules[row] = HighLineColor
+	}
+	if row := ruleRow(crit, hasCrit, height, rangeMin, rangeMax); row >= 0 {
+		rules[row] = CritLineColor
+	}
+	return renderBars(points, width, height, rangeMin, rangeMax, high, crit, hasHigh, hasCrit, rules)
+}
+
+// RenderWatermark renders points like RenderTall, with a dim rule at peak
+// instead of the limit lines, marking how high the series got.
+func RenderWatermark(points []history.Point, width, height int, rangeMin, rangeMax, peak, high, crit float64, hasHigh, hasCrit bool) []string {
+	rules := map[int]lipgloss.Color{}
+	if row := ruleRow(peak, true, height, rangeMin, rangeMax); row >= 0 {
+		rules[row] = WatermarkColor
+	}
+	return renderBars(points, width, height, rangeMin, rangeMax, high, crit, hasHigh, hasCrit, rules)
+}
+
+// ruleRow returns the row of a height-row chart that v falls in, top row
+// 0, or -1 when v is unset or off the chart.
+func ruleRow(v float64, ok bool, height int, rangeMin, rangeMax float64) int {
+	if !ok || v < rangeMin || v > rangeMax || rangeMax <= rangeMin {
+		return -1
+	}
+	return height - 1 - min(height-1, int((v-rangeMin)/(rangeMax-rangeMin)*float64(height)))
+}
+
+// renderBars draws the bar chart for RenderTall and RenderWatermark, with
+// a '─' rule in the given color across the empty cells of each rules row.
+func renderBars(points []history.Point, width, height int, rangeMin, rangeMax, high, crit float64, hasHigh, hasCrit bool, rules map[int]lipgloss.Color) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	if len(points) > width {
+		points = points[len(points)-width:]
+	}
+	span := rangeMax - rangeMin
+	if span <= 0 {
+		span = 1
+	}
+	padLen := width - len(points)
+
+	rows := make([]string, height)
+	for row := range rows {
+		var sb strings.Builder
+		var run []rune
+		var runColor lipgloss.Color
+		flush := func() {
+			if len(run) > 0 {
+				sb.WriteString(lipgloss.NewStyle().Foreground(runColor).Render(string(run)))
+				run = run[:0]
+			}
+		}
+		cell := func(ch rune, color lipgloss.Color) {
+			if color != runColor {
+				flush()
+				runColor = color
+			}
+			run = append(run, ch)
+		}
+
+		// Eighths of a row filled below this row's top edge
+		base := (height - 1 - row) * 8
+		for i := 0; i < width; i++ {
+			if i >= padLen {
+				p := points[i-padLen]
+				level := int(math.Max(0, math.Min(1, (p.Temp-rangeMin)/span)) * float64(height*8))
+				if fill := min(8, level-base); fill > 0 {
+					cell(sparkBlocks[fill-1], TempColor(p.Temp, high, crit, hasHigh, hasCrit))
+					continue
+				}
+			}
+			if color, ok := rules[row]; ok {
+				cell('─', color)
+			} else {
+				cell(' ', "")
+			}
+		}
+		flush()
+		rows[row] = sb.String()
+	}
+	return rows
+}
+
+// hasMarker reports whether any marker falls in (points[i-1].Time,
+// points[i].Time], or exactly on the first point's time.
+func hasMarker(points []history.Point, i int, markers []time.Time) bool {
+	t := points[i].Time
+	if t.IsZero() {
+		return false
+	}
+	prev := t
+	if i > 0 && !points[i-1].Time.IsZero() {
+		prev = points[i-1].Time
+	}
+	for _, mk := range markers {
+		if mk.Equal(t) || (mk.After(prev) && !mk.After(t)) {
+			return true
+		}
+	}
+	return false
+}
+
 // RenderTimeline renders the time labels under the sparkline, showing
 // HH:MM at each minute tick position.
 func RenderTimeline(points []history.Point, width int) string {
@@ -236,7 +531,7 @@ func RenderThresholdScale(current, rangeMin, rangeMax, high, crit float64, hasHi
 				sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("\u25AA"))
 			}
 		} else {
-			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("236")).Render(string(ch)))
+			sb.WriteString(lipgloss.NewStyle().Foreground(DimGray("236")).Render(string(ch)))
 		}
 	}
 
@@ -245,7 +540,7 @@ func RenderThresholdScale(current, rangeMin, rangeMax, high, crit float64, hasHi
 
 // RenderTempValue renders the temperature value with color coding.
 func RenderTempValue(temp, high, crit float64, hasHigh, hasCrit bool) string {
-	s := fmt.Sprintf("%5.1f\u00B0C", temp)
+	s := Num("%5.1f", temp) + "\u00B0C"
 	color := TempColor(temp, high, crit, hasHigh, hasCrit)
 	style := lipgloss.NewStyle().Foreground(color)
 	if hasCrit && temp >= crit {
@@ -253,3 +548,13 @@ func RenderTempValue(temp, high, crit float64, hasHigh, hasCrit bool) string {
 	}
 	return style.Render(s)
 }
+
+// RenderUnitValue renders a non-temperature value (power, fan speed) with
+// its unit. These have no thresholds, so they use a neutral color.
+func RenderUnitValue(v float64, unit string) string {
+	s := fmt.Sprintf("%5.0f%s", v, unit)
+	if unit == "W" {
+		s = Num("%5.1f", v) + unit
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("250")).Render(s)
+}
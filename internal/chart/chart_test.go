@@ -4,8 +4,13 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
 	"github.com/luki/sensors/internal/history"
+	"github.com/luki/sensors/internal/testutil"
 )
 
 func TestSparkline(t *testing.T) {
@@ -36,3 +41,334 @@ func TestSparklineMinuteTicks(t *testing.T) {
 	}
 	t.Logf("Sparkline with ticks: %s", result)
 }
+
+func TestWriteSVG(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var pts []history.Point
+	for i := 0; i < 30; i++ {
+		pts = append(pts, history.Point{Temp: float64(40 + i%7), Time: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	var sb strings.Builder
+	err := WriteSVG(&sb, []Series{
+		{Name: "CPU coretemp-isa-0000/Core 0", Points: pts, High: 80, Crit: 100, HasHigh: true, HasCrit: true},
+		{Name: "empty <sensor>"},
+	}, 800)
+	if err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+
+	out := sb.String()
+	if !strings.HasPrefix(out, "<svg") || !strings.HasSuffix(out, "</svg>\n") {
+		t.Error("expected a complete svg document")
+	}
+	if strings.Count(out, "<polyline") != 1 {
+		t.Errorf("expected one polyline for the non-empty series, got %d", strings.Count(out, "<polyline"))
+	}
+	if !strings.Contains(out, "empty &lt;sensor&gt;") {
+		t.Error("expected series name to be escaped")
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var pts []history.Point
+	for i := 0; i < 600; i++ {
+		pts = append(pts, history.Point{Temp: 40 + float64(i)/20, Time: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	out := RenderASCII(pts, 60, 10)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("expected 10 rows + axis + time labels, got %d lines:\n%s", len(lines), out)
+	}
+	if strings.Contains(out, "\x1b") {
+		t.Error("ASCII chart must not contain ANSI escapes")
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "        14:00") {
+		t.Errorf("expected start time label, got %q", lines[len(lines)-1])
+	}
+	t.Logf("\n%s", out)
+}
+
+func TestTempColorThemeOverride(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	defer SetTheme(DefaultTheme())
+
+	// 80% of high is below the default 85% warm onset
+	if got := TempColor(64, 80, 100, true, true); got != DefaultTheme().Ok {
+		t.Errorf("default at 80%% of high: got %v, want ok color", got)
+	}
+
+	SetTheme(Theme{WarmFraction: 0.75, Warm: lipgloss.Color("226"), Crit: lipgloss.Color("160")})
+	if got := TempColor(64, 80, 100, true, true); got != lipgloss.Color("226") {
+		t.Errorf("warm_fraction 0.75 at 80%% of high: got %v, want 226", got)
+	}
+	if got := TempColor(100, 80, 100, true, true); got != lipgloss.Color("160") {
+		t.Errorf("custom crit color: got %v, want 160", got)
+	}
+	// Unset fields keep their defaults
+	if got := TempColor(85, 80, 100, true, true); got != DefaultTheme().High {
+		t.Errorf("unset high color: got %v, want default", got)
+	}
+}
+
+func TestTempColorPartialThresholds(t *testing.T) {
+	defer SetTheme(DefaultTheme())
+	SetTheme(DefaultTheme())
+	th := DefaultTheme()
+
+	tests := []struct {
+		name             string
+		v, high, crit    float64
+		hasHigh, hasCrit bool
+		want             lipgloss.Color
+	}{
+		{"crit only, well below", 70, 0, 100, false, true, th.Ok},
+		{"crit only, within 10%", 92, 0, 100, false, true, th.Warm},
+		{"crit only, at crit", 100, 0, 100, false, true, th.Crit},
+		{"high only, below warm", 60, 80, 0, true, false, th.Ok},
+		{"high only, warm", 70, 80, 0, true, false, th.Warm},
+		{"high only, far above", 150, 80, 0, true, false, th.High},
+		{"no limits", 150, 0, 0, false, false, th.Ok},
+	}
+	for _, tt := range tests {
+		if got := TempColor(tt.v, tt.high, tt.crit, tt.hasHigh, tt.hasCrit); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalComma(t *testing.T) {
+	for locale, want := range map[string]bool{
+		"": false, "C": false, "POSIX": false, "en_US.UTF-8": false,
+		"de_DE.UTF-8": true, "fr_FR": true, "pt_BR.utf8": true, "de_CH.UTF-8": false, "nl_NL@euro": true,
+	} {
+		if got := DecimalComma(locale); got != want {
+			t.Errorf("DecimalComma(%q) = %v, want %v", locale, got, want)
+		}
+	}
+
+	defer SetDecimalComma(false)
+	if got := Num("%5.1f", 42.25); got != " 42.2" {
+		t.Errorf("default separator: got %q", got)
+	}
+	SetDecimalComma(true)
+	if got := Num("%5.1f", -3.5); got != " -3,5" {
+		t.Errorf("comma separator: got %q", got)
+	}
+	if got := testutil.SGR.ReplaceAllString(RenderTempValue(61.5, 80, 100, true, true), ""); got != " 61,5°C" {
+		t.Errorf("RenderTempValue: got %q", got)
+	}
+}
+
+// benchPoints is a 10 minute series crossing all temperature bands, the
+// shape the monitor renders once per sensor per poll.
+func benchPoints() []history.Point {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	pts := make([]history.Point, 600)
+	for i := range pts {
+		pts[i] = history.Point{Temp: 40 + float64(i%120)/2, Time: base.Add(time.Duration(i) * time.Second)}
+	}
+	return pts
+}
+
+func BenchmarkRenderSparklinePoints(b *testing.B) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	pts := benchPoints()
+	b.ReportAllocs()
+	for b.Loop() {
+		RenderSparklinePoints(pts, 140, 30, 105, 80, 95, true, true)
+	}
+}
+
+func TestSparklineCoalescesRuns(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI256)
+
+	// Monotone and all in the ok band: one run, so one color escape and
+	// one reset instead of a pair per cell
+	values := make([]float64, 40)
+	for i := range values {
+		values[i] = 40 + float64(i)/4
+	}
+	out := RenderSparkline(values, 40, 30, 100, 80, 95, true, true)
+	if n := len(testutil.SGR.FindAllString(out, -1)); n != 2 {
+		t.Errorf("monotone series: %d escapes, want 2", n)
+	}
+	if got := testutil.SGR.ReplaceAllString(out, ""); utf8.RuneCountInString(got) != 40 {
+		t.Errorf("monotone series: %d cells, want 40", utf8.RuneCountInString(got))
+	}
+
+	// A minute tick still splits the run
+	base := time.Date(2026, 2, 21, 14, 0, 50, 0, time.Local)
+	var pts []history.Point
+	for i := 0; i < 20; i++ {
+		pts = append(pts, history.Point{Temp: 45, Time: base.Add(time.Duration(i) * time.Second)})
+	}
+	out = RenderSparklinePoints(pts, 20, 30, 100, 80, 95, true, true)
+	plain := testutil.SGR.ReplaceAllString(out, "")
+	if want := strings.Repeat("▂", 10) + "│" + strings.Repeat("▂", 9); plain != want {
+		t.Errorf("ticked series: got %q, want %q", plain, want)
+	}
+	if n := len(testutil.SGR.FindAllString(out, -1)); n != 6 {
+		t.Errorf("ticked series: %d escapes, want 6 (three runs)", n)
+	}
+}
+
+func TestRenderTallLimitLines(t *testing.T) {
+	var pts []history.Point
+	for i := 0; i < 10; i++ {
+		pts = append(pts, history.Point{Temp: 40 + float64(i)})
+	}
+	// 0-100 over 10 rows: high 80 sits on row 1, crit 95 on row 0
+	rows := RenderTall(pts, 12, 10, 0, 100, 80, 95, true, true)
+	if len(rows) != 10 {
+		t.Fatalf("got %d rows, want 10", len(rows))
+	}
+	plain := make([]string, len(rows))
+	for i, r := range rows {
+		plain[i] = testutil.SGR.ReplaceAllString(r, "")
+		if n := utf8.RuneCountInString(plain[i]); n != 12 {
+			t.Errorf("row %d: %d cells, want 12", i, n)
+		}
+	}
+	if want := strings.Repeat("─", 12); plain[0] != want || plain[1] != want {
+		t.Errorf("limit rows = %q, %q, want full rules", plain[0], plain[1])
+	}
+	if strings.Contains(strings.Join(plain[2:], ""), "─") {
+		t.Error("a rule was drawn off the limit rows")
+	}
+	// Right-aligned: two empty columns, then bars filling the bottom rows
+	if want := "  " + strings.Repeat("█", 10); plain[9] != want {
+		t.Errorf("bottom row = %q, want %q", plain[9], want)
+	}
+
+	// Limits above the range are left off
+	rows = RenderTall(pts, 12, 10, 0, 60, 80, 95, true, true)
+	if strings.Contains(testutil.SGR.ReplaceAllString(strings.Join(rows, ""), ""), "─") {
+		t.Error("limits outside the range should not be drawn")
+	}
+}
+
+func TestSparklineBelowZero(t *testing.T) {
+	// A freezer probe: every reading is below zero and must still get
+	// its own level instead of sitting on a 0°C floor
+	values := []float64{-30, -24, -18, -12, -6}
+	lo, hi := Range(-30, -6, "")
+	if lo != -35 || hi != -1 {
+		t.Errorf("Range(-30, -6) = %v, %v, want -35, -1", lo, hi)
+	}
+	plain := []rune(testutil.SGR.ReplaceAllString(RenderSparkline(values, len(values), lo, hi, 0, 0, false, false), ""))
+	if len(plain) != len(values) {
+		t.Fatalf("got %d cells, want %d", len(plain), len(values))
+	}
+	for i := 1; i < len(plain); i++ {
+		if plain[i] <= plain[i-1] {
+			t.Errorf("sub-zero readings not differentiated: %q", string(plain))
+			break
+		}
+	}
+
+	// Non-temperature units still floor at zero
+	if lo, _ := Range(0.1, 3, "W"); lo != 0 {
+		t.Errorf("Range(0.1, 3, W) floor = %v, want 0", lo)
+	}
+}
+
+func TestSensorRangeFixed(t *testing.T) {
+	defer SetFixedRanges(nil)
+	SetFixedRanges(map[string]FixedRange{"CPU": {Min: 30, Max: 100}})
+
+	if lo, hi := SensorRange("CPU", "", 55, 60, 80, 100, true, true); lo != 30 || hi != 100 {
+		t.Errorf("fixed CPU range = %v..%v, want 30..100", lo, hi)
+	}
+	// CPU load shares the component but is not a temperature
+	if lo, hi := SensorRange("CPU", "%", 10, 90, 0, 0, false, false); lo == 30 && hi == 100 {
+		t.Error("fixed range applied to a non-temperature reading")
+	}
+	if lo, hi := SensorRange("NVMe SSD", "", 40, 50, 82, 85, true, true); lo != 35 || hi != 90 {
+		t.Errorf("auto range = %v..%v, want 35..90 (crit plus padding)", lo, hi)
+	}
+}
+
+func TestSetThemeANSIFallback(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+	defer SetTheme(DefaultTheme())
+
+	SetTheme(Theme{Crit: lipgloss.Color("160")})
+	if got := TempColor(85, 80, 100, true, true); got != ANSITheme().High {
+		t.Errorf("16-color default high: got %v, want %v", got, ANSITheme().High)
+	}
+	if got := TempColor(100, 80, 100, true, true); got != lipgloss.Color("160") {
+		t.Errorf("configured crit should be kept: got %v", got)
+	}
+}
+
+func TestCollecting(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.UTC)
+	pt := func(sec int, v float64) history.Point {
+		return history.Point{Temp: v, Time: base.Add(time.Duration(sec) * time.Second)}
+	}
+
+	for _, tt := range []struct {
+		pts  []history.Point
+		want bool
+	}{
+		{nil, true},
+		{[]history.Point{pt(0, 50)}, true},
+		{[]history.Point{pt(0, 50), pt(1, 50)}, true},
+		{[]history.Point{pt(0, 50), pt(1, 51)}, false},
+		{[]history.Point{pt(0, 50), pt(1, 50), pt(2, 50)}, false},
+	} {
+		if got := Collecting(tt.pts); got != tt.want {
+			t.Errorf("Collecting(%d points) = %v, want %v", len(tt.pts), got, tt.want)
+		}
+	}
+
+	got := testutil.SGR.ReplaceAllString(RenderCollecting(14), "")
+	if got != "collecting…╌╌╌" {
+		t.Errorf("RenderCollecting(14) = %q", got)
+	}
+	if got := testutil.SGR.ReplaceAllString(RenderCollecting(4), ""); got != "coll" {
+		t.Errorf("RenderCollecting(4) = %q", got)
+	}
+}
+
+func TestDimGrayANSI(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	// 236 would round to black; the dashes must stay visible
+	out := RenderCollecting(20)
+	if !strings.Contains(out, "\x1b[90m") || strings.Contains(out, "\x1b[30m") {
+		t.Errorf("16-color dashes should be bright black: %q", out)
+	}
+}
+
+func TestTruncateWide(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"Package id 0", 16, "Package id 0"},
+		{"Samsung SSD 870 EVO", 10, "Samsung S…"},
+		{"温度センサー", 8, "温度セ…"},          // 2 cells per rune
+		{"温度センサー", 6, "温度…"},           // a wide rune never straddles the cut
+		{"GPU 🔥 hotspot", 7, "GPU 🔥…"}, // the emoji takes 2 cells
+		{"Ünïcödé", 3, "Ünï"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.in, tt.w)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.w, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.w {
+			t.Errorf("Truncate(%q, %d) is %d cells wide", tt.in, tt.w, w)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+)
+
+// decimalComma makes Num write a comma as the decimal separator.
+var decimalComma bool
+
+// SetDecimalComma switches displayed numbers between "42.5" (the default)
+// and "42,5". Files and machine-readable output always use a dot.
+func SetDecimalComma(on bool) {
+	decimalComma = on
+}
+
+// commaLanguages are the languages whose locales write decimals with a
+// comma. Regions that differ from their language, such as de_CH, are in
+// commaExceptions.
+var (
+	commaLanguages = map[string]bool{
+		"bg": true, "ca": true, "cs": true, "da": true, "de": true, "el": true,
+		"es": true, "et": true, "eu": true, "fi": true, "fr": true, "gl": true,
+		"hr": true, "hu": true, "id": true, "is": true, "it": true, "lt": true,
+		"lv": true, "nb": true, "nl": true, "nn": true, "no": true, "pl": true,
+		"pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sr": true,
+		"sv": true, "tr": true, "uk": true, "vi": true,
+	}
+	commaExceptions = map[string]bool{"de_CH": true, "de_LI": true, "it_CH": true}
+)
+
+// DecimalComma reports whether a POSIX locale name such as "de_DE.UTF-8"
+// writes decimals with a comma. "C", "POSIX" and "" do not.
+func DecimalComma(locale string) bool {
+	name, _, _ := strings.Cut(locale, ".")
+	name, _, _ = strings.Cut(name, "@")
+	lang, _, _ := strings.Cut(name, "_")
+	return commaLanguages[lang] && !commaExceptions[name]
+}
+
+// Num formats v like fmt.Sprintf(format, v), with the decimal separator
+// chosen by SetDecimalComma. Widths are unaffected since both separators
+// are one column.
+func Num(format string, v float64) string {
+	s := fmt.Sprintf(format, v)
+	if decimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
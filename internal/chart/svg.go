@@ -0,0 +1,107 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/luki/sensors/internal/history"
+)
+
+// Series is one sensor's history for vector export.
+type Series struct {
+	Name      string
+	Points    []history.Point
+	High      float64
+	Crit      float64
+	HasHigh   bool
+	HasCrit   bool
+	Unit      string // "" for temperature
+	Component string // FriendlyName, for a configured fixed range
+}
+
+const (
+	svgPanelHeight = 140
+	svgMarginLeft  = 50
+	svgMarginRight = 20
+	svgPlotTop     = 24
+	svgPlotBottom  = 22
+)
+
+// WriteSVG renders each series as its own line chart, stacked vertically,
+// into a standalone SVG document of the given pixel width. High and crit
+// thresholds are drawn as dashed reference lines.
+func WriteSVG(w io.Writer, series []Series, width int) error {
+	height := svgPanelHeight * len(series)
+	if height == 0 {
+		height = svgPanelHeight
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="11">`+"\n", width, height)
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="#111"/>`+"\n")
+
+	for i, s := range series {
+		writeSVGPanel(&sb, s, i*svgPanelHeight, width)
+	}
+
+	sb.WriteString("</svg>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeSVGPanel(sb *strings.Builder, s Series, top, width int) {
+	plotW := float64(width - svgMarginLeft - svgMarginRight)
+	plotH := float64(svgPanelHeight - svgPlotTop - svgPlotBottom)
+	x0 := float64(svgMarginLeft)
+	y0 := float64(top + svgPlotTop)
+
+	fmt.Fprintf(sb, `<text x="%d" y="%d" fill="#afafff">%s</text>`+"\n", svgMarginLeft, top+16, html.EscapeString(s.Name))
+
+	if len(s.Points) == 0 {
+		return
+	}
+
+	lo, hi := math.MaxFloat64, -math.MaxFloat64
+	for _, p := range s.Points {
+		lo = math.Min(lo, p.Temp)
+		hi = math.Max(hi, p.Temp)
+	}
+	rangeMin, rangeMax := SensorRange(s.Component, s.Unit, lo, hi, s.High, s.Crit, s.HasHigh, s.HasCrit)
+	span := rangeMax - rangeMin
+
+	start := s.Points[0].Time
+	dur := s.Points[len(s.Points)-1].Time.Sub(start).Seconds()
+
+	yOf := func(v float64) float64 { return y0 + plotH - (v-rangeMin)/span*plotH }
+	xOf := func(i int, p history.Point) float64 {
+		if dur > 0 {
+			return x0 + p.Time.Sub(start).Seconds()/dur*plotW
+		}
+		if len(s.Points) > 1 {
+			return x0 + float64(i)/float64(len(s.Points)-1)*plotW
+		}
+		return x0
+	}
+
+	fmt.Fprintf(sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="#333"/>`+"\n", x0, y0, plotW, plotH)
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" fill="#888" text-anchor="end">%.0f</text>`+"\n", x0-4, y0+8, rangeMax)
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" fill="#888" text-anchor="end">%.0f</text>`+"\n", x0-4, y0+plotH, rangeMin)
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" fill="#888">%s</text>`+"\n", x0, y0+plotH+14, start.Format("15:04:05"))
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" fill="#888" text-anchor="end">%s</text>`+"\n", x0+plotW, y0+plotH+14, s.Points[len(s.Points)-1].Time.Format("15:04:05"))
+
+	if s.HasHigh {
+		fmt.Fprintf(sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ffd75f" stroke-dasharray="4 3"/>`+"\n", x0, yOf(s.High), x0+plotW, yOf(s.High))
+	}
+	if s.HasCrit {
+		fmt.Fprintf(sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ff0000" stroke-dasharray="4 3"/>`+"\n", x0, yOf(s.Crit), x0+plotW, yOf(s.Crit))
+	}
+
+	pts := make([]string, len(s.Points))
+	for i, p := range s.Points {
+		pts[i] = fmt.Sprintf("%.1f,%.1f", xOf(i, p), yOf(p.Temp))
+	}
+	fmt.Fprintf(sb, `<polyline points="%s" fill="none" stroke="#5fd787" stroke-width="1.5"/>`+"\n", strings.Join(pts, " "))
+}
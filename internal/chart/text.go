@@ -0,0 +1,34 @@
+package chart
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Truncate shortens s to at most w terminal cells, ending in an ellipsis
+// when there is room for one. Wide runes such as CJK and emoji count as
+// two cells and are never split.
+func Truncate(s string, w int) string {
+	if lipgloss.Width(s) <= w {
+		return s
+	}
+	limit := w
+	if w > 3 {
+		limit = w - 1
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if used+rw > limit {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	if w > 3 {
+		b.WriteString("\u2026")
+	}
+	return b.String()
+}
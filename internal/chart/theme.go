@@ -0,0 +1,91 @@
+package chart
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme holds the temperature band colors used by TempColor and the
+// fraction of the high threshold at which the warm band starts.
+type Theme struct {
+	WarmFraction float64
+	Ok           lipgloss.Color
+	Warm         lipgloss.Color
+	High         lipgloss.Color
+	Crit         lipgloss.Color
+}
+
+// DefaultTheme returns the built-in bands: green, yellow from 85% of high,
+// orange at high and red at crit.
+func DefaultTheme() Theme {
+	return Theme{
+		WarmFraction: 0.85,
+		Ok:           lipgloss.Color("78"),  // soft green
+		Warm:         lipgloss.Color("220"), // yellow
+		High:         lipgloss.Color("208"), // orange
+		Crit:         lipgloss.Color("196"), // red
+	}
+}
+
+// ANSITheme returns the bands in the 16 basic ANSI colors for terminals
+// without 256-color support, where the defaults would be approximated and
+// orange has no close match: green, yellow, red and bright red.
+func ANSITheme() Theme {
+	return Theme{
+		WarmFraction: 0.85,
+		Ok:           lipgloss.Color("2"),
+		Warm:         lipgloss.Color("3"),
+		High:         lipgloss.Color("1"),
+		Crit:         lipgloss.Color("9"),
+	}
+}
+
+// defaultTheme returns the built-in bands suited to a color profile.
+func defaultTheme(p termenv.Profile) Theme {
+	if p >= termenv.ANSI {
+		return ANSITheme()
+	}
+	return DefaultTheme()
+}
+
+var theme = DefaultTheme()
+
+// DimGray returns c, one of the dark 256-color grays (236, 237) used for
+// empty sparkline cells and dimmed chrome, or bright black on a 16-color
+// terminal, where grays that dark round to black and vanish on a dark
+// background.
+func DimGray(c lipgloss.Color) lipgloss.Color {
+	if lipgloss.ColorProfile() == termenv.ANSI {
+		return lipgloss.Color("8")
+	}
+	return c
+}
+
+// SetTheme replaces the active theme. Empty colors and a non-positive
+// WarmFraction keep their defaults, which follow the terminal's color
+// profile: ANSITheme on a 16-color or monochrome terminal. Configured
+// 256-color or hex colors are mapped to the nearest ANSI color there.
+func SetTheme(t Theme) {
+	def := defaultTheme(lipgloss.ColorProfile())
+	if t.WarmFraction <= 0 {
+		t.WarmFraction = def.WarmFraction
+	}
+	if t.Ok == "" {
+		t.Ok = def.Ok
+	}
+	if t.Warm == "" {
+		t.Warm = def.Warm
+	}
+	if t.High == "" {
+		t.High = def.High
+	}
+	if t.Crit == "" {
+		t.Crit = def.Crit
+	}
+	theme = t
+}
+
+// ActiveTheme returns the theme TempColor currently uses.
+func ActiveTheme() Theme {
+	return theme
+}
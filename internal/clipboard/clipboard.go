@@ -0,0 +1,45 @@
+// Package clipboard copies text to the system clipboard through whichever
+// command-line tool is installed: wl-copy on Wayland, xclip or xsel on
+// X11, pbcopy on macOS.
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool is installed or no
+// display is available to it.
+var ErrUnavailable = errors.New("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+
+// tool is a clipboard command and the environment it needs to work.
+type tool struct {
+	env  string // display variable that must be set; "" for none
+	name string
+	args []string
+}
+
+var tools = []tool{
+	{"WAYLAND_DISPLAY", "wl-copy", nil},
+	{"DISPLAY", "xclip", []string{"-selection", "clipboard"}},
+	{"DISPLAY", "xsel", []string{"--clipboard", "--input"}},
+	{"", "pbcopy", nil},
+}
+
+// Copy puts text on the clipboard using the first usable tool.
+func Copy(text string) error {
+	for _, t := range tools {
+		if t.env != "" && os.Getenv(t.env) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(t.name); err != nil {
+			continue
+		}
+		cmd := exec.Command(t.name, t.args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrUnavailable
+}
@@ -0,0 +1,35 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "clip")
+	script := "#!/bin/sh\ncat > " + out + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "wl-copy"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+":/bin:/usr/bin")
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+
+	if err := Copy("coretemp-isa-0000 Core 0: 72.0°C"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	got, _ := os.ReadFile(out)
+	if string(got) != "coretemp-isa-0000 Core 0: 72.0°C" {
+		t.Errorf("clipboard got %q", got)
+	}
+
+	// Without a display the tool is skipped
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("DISPLAY", "")
+	t.Setenv("PATH", dir)
+	if err := Copy("x"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("no display: got %v, want ErrUnavailable", err)
+	}
+}
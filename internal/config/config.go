@@ -0,0 +1,336 @@
+// Package config loads user settings from ~/.config/sensors/config.toml
+// and SENSORS_* environment variables. Precedence, highest first, is
+// command-line flag > environment > config file > built-in default; flags
+// are applied by the caller using the loaded values as flag defaults.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config holds all user-tunable settings.
+type Config struct {
+	Interval       time.Duration `toml:"interval"`        // poll interval, e.g. "1s"
+	HistorySize    int           `toml:"history_size"`    // points kept per sensor in the live monitor
+	ExpirePolls    int           `toml:"expire_polls"`    // polls a sensor may be missing before the live monitor drops it
+	DataDir        string        `toml:"data_dir"`        // CSV directory; "" means ~/.sensors-data
+	Store          string        `toml:"store"`           // day file format, "csv" or "jsonl"
+	Webhook        string        `toml:"webhook"`         // crit alert URL; "" disables
+	OnCrit         string        `toml:"on_crit"`         // shell command run when a sensor stays above crit; "" disables
+	CritSustain    time.Duration `toml:"crit_sustain"`    // how long a sensor must stay above crit before on_crit runs
+	OutlierDelta   float64       `toml:"outlier_delta"`   // °C from the recent average that marks a reading suspect; 0 disables
+	CohortSigma    float64       `toml:"cohort_sigma"`    // standard deviations above its like-named siblings (Core 0, Core 1, ...) that mark a sensor; 0 disables
+	MaxGap         time.Duration `toml:"max_gap"`         // history viewer shows "--" when the nearest reading is further away
+	HistoryWindow  time.Duration `toml:"history_window"`  // time spanned by each history viewer sparkline
+	Timezone       string        `toml:"timezone"`        // IANA zone for day files and timestamps, e.g. "UTC"; "" means the system zone
+	Millis         bool          `toml:"millis"`          // write CSV timestamps with milliseconds, for sub-second intervals
+	StressDuration time.Duration `toml:"stress_duration"` // stress run length when none is given on the command line
+	Hidden         []string      `toml:"hidden"`          // chip/label keys the live monitor does not show; toggled with x
+	Pinned         []string      `toml:"pinned"`          // chip/label keys the live monitor shows first, in order; toggled with t
+
+	Theme      Theme                          `toml:"theme"`
+	Thresholds map[string]ComponentThresholds `toml:"thresholds"` // fallback limits keyed by component name, e.g. "HDD/SSD"
+	Ranges     map[string]ChartRange          `toml:"ranges"`     // fixed chart ranges keyed by component name, e.g. "CPU"
+	Chips      map[string]string              `toml:"chips"`      // component names keyed by chip ID prefix, checked before the built-in map
+}
+
+// Theme tunes the temperature color bands. Colors are lipgloss colors:
+// ANSI 256 numbers such as "78" or hex such as "#5fd787". Empty colors
+// keep the built-in defaults.
+type Theme struct {
+	WarmFraction float64 `toml:"warm_fraction"` // fraction of high where the warm band starts
+	Ok           string  `toml:"ok"`
+	Warm         string  `toml:"warm"`
+	High         string  `toml:"high"`
+	Crit         string  `toml:"crit"`
+}
+
+// ComponentThresholds are the high/crit limits given to a component's
+// sensors when the hardware reports none. Zero leaves a limit unset.
+type ComponentThresholds struct {
+	High float64 `toml:"high"`
+	Crit float64 `toml:"crit"`
+}
+
+// ChartRange pins the chart range of a component's temperatures instead
+// of following the data, so charts stay comparable over a long run.
+type ChartRange struct {
+	Min float64 `toml:"min"`
+	Max float64 `toml:"max"`
+}
+
+// Default returns the built-in defaults.
+func Default() Config {
+	return Config{
+		Interval:       1 * time.Second,
+		HistorySize:    600,
+		ExpirePolls:    30,
+		CohortSigma:    2,
+		CritSustain:    30 * time.Second,
+		Store:          "csv",
+		MaxGap:         2 * time.Minute,
+		HistoryWindow:  10 * time.Minute,
+		StressDuration: 60 * time.Second,
+		Theme:          Theme{WarmFraction: 0.85},
+	}
+}
+
+// Path returns the config file location, honoring XDG_CONFIG_HOME.
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sensors", "config.toml")
+}
+
+// Load returns the defaults overlaid with the config file (if present) and
+// then the environment.
+func Load() (Config, error) {
+	cfg := Default()
+	if err := cfg.loadFile(Path()); err != nil {
+		return cfg, err
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return cfg, err
+	}
+	if cfg.OutlierDelta < 0 {
+		return cfg, fmt.Errorf("outlier_delta must not be negative, got %v", cfg.OutlierDelta)
+	}
+	if cfg.CritSustain < 0 {
+		return cfg, fmt.Errorf("crit_sustain must not be negative, got %v", cfg.CritSustain)
+	}
+	if cfg.CohortSigma < 0 {
+		return cfg, fmt.Errorf("cohort_sigma must not be negative, got %v", cfg.CohortSigma)
+	}
+	if _, err := cfg.Location(); err != nil {
+		return cfg, err
+	}
+	for name, t := range cfg.Thresholds {
+		if t.High < 0 || t.Crit < 0 {
+			return cfg, fmt.Errorf("thresholds.%q: limits must not be negative", name)
+		}
+	}
+	for name, r := range cfg.Ranges {
+		if r.Max <= r.Min {
+			return cfg, fmt.Errorf("ranges.%q: max must be above min", name)
+		}
+	}
+	for prefix, name := range cfg.Chips {
+		// A blank name would leave the panel header and `status` nothing to show
+		if strings.TrimSpace(prefix) == "" || strings.TrimSpace(name) == "" {
+			return cfg, fmt.Errorf("chips: prefix and name must not be empty, got %q = %q", prefix, name)
+		}
+	}
+	if f := cfg.Theme.WarmFraction; f <= 0 || f > 1 {
+		return cfg, fmt.Errorf("theme.warm_fraction must be in (0, 1], got %v", f)
+	}
+	return cfg, nil
+}
+
+// Location resolves Timezone, defaulting to the system zone.
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := toml.DecodeFile(path, c); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveHidden writes the monitor's hidden sensor keys to the config file.
+func SaveHidden(keys []string) error {
+	return saveList(Path(), "hidden", keys)
+}
+
+// SavePinned writes the monitor's pinned sensor keys to the config file.
+func SavePinned(keys []string) error {
+	return saveList(Path(), "pinned", keys)
+}
+
+var tableHeader = regexp.MustCompile(`^\s*\[`)
+
+// saveList sets a top-level string list in the config file, creating the
+// file if needed. The rest of the file, comments included, is kept as
+// written: an existing assignment is replaced in place, otherwise the
+// line goes before the first table.
+func saveList(path, key string, values []string) error {
+	var buf bytes.Buffer
+	if values == nil {
+		values = []string{}
+	}
+	if err := toml.NewEncoder(&buf).Encode(map[string][]string{key: values}); err != nil {
+		return err
+	}
+	line := strings.TrimSpace(buf.String())
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	assign := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	insert := len(lines)
+	for i := 0; i < len(lines); i++ {
+		if tableHeader.MatchString(lines[i]) {
+			insert = i
+			break
+		}
+		if !assign.MatchString(lines[i]) {
+			continue
+		}
+		// A list may continue over several lines up to its closing bracket
+		end := i
+		for !strings.Contains(stripComment(lines[end]), "]") && end+1 < len(lines) {
+			end++
+		}
+		lines = append(lines[:i], append([]string{line}, lines[end+1:]...)...)
+		insert = -1
+		break
+	}
+	if insert >= 0 {
+		added := []string{line}
+		if insert < len(lines) {
+			added = append(added, "")
+		}
+		lines = append(lines[:insert], append(added, lines[insert:]...)...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// stripComment drops a trailing # comment, ignoring # inside strings.
+func stripComment(line string) string {
+	inString := false
+	for i, c := range line {
+		switch {
+		case c == '"' && (i == 0 || line[i-1] != '\\'):
+			inString = !inString
+		case c == '#' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func (c *Config) loadEnv() error {
+	if v := os.Getenv("SENSORS_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_INTERVAL: %w", err)
+		}
+		c.Interval = d
+	}
+	if v := os.Getenv("SENSORS_HISTORY_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_HISTORY_SIZE: %w", err)
+		}
+		c.HistorySize = n
+	}
+	if v := os.Getenv("SENSORS_EXPIRE_POLLS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_EXPIRE_POLLS: %w", err)
+		}
+		c.ExpirePolls = n
+	}
+	if v := os.Getenv("SENSORS_DATA_DIR"); v != "" {
+		c.DataDir = v
+	}
+	if v := os.Getenv("SENSORS_STORE"); v != "" {
+		c.Store = v
+	}
+	if v := os.Getenv("SENSORS_WEBHOOK"); v != "" {
+		c.Webhook = v
+	}
+	if v := os.Getenv("SENSORS_ON_CRIT"); v != "" {
+		c.OnCrit = v
+	}
+	if v := os.Getenv("SENSORS_CRIT_SUSTAIN"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_CRIT_SUSTAIN: %w", err)
+		}
+		c.CritSustain = d
+	}
+	if v := os.Getenv("SENSORS_MAX_GAP"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_MAX_GAP: %w", err)
+		}
+		c.MaxGap = d
+	}
+	if v := os.Getenv("SENSORS_HISTORY_WINDOW"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_HISTORY_WINDOW: %w", err)
+		}
+		c.HistoryWindow = d
+	}
+	if v := os.Getenv("SENSORS_TIMEZONE"); v != "" {
+		c.Timezone = v
+	}
+	if v := os.Getenv("SENSORS_MILLIS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_MILLIS: %w", err)
+		}
+		c.Millis = b
+	}
+	if v := os.Getenv("SENSORS_STRESS_DURATION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SENSORS_STRESS_DURATION: %w", err)
+		}
+		c.StressDuration = d
+	}
+	if v := os.Getenv("SENSORS_OUTLIER_DELTA"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("SENSORS_OUTLIER_DELTA: %w", err)
+		}
+		c.OutlierDelta = f
+	}
+	if v := os.Getenv("SENSORS_COHORT_SIGMA"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("SENSORS_COHORT_SIGMA: %w", err)
+		}
+		c.CohortSigma = f
+	}
+	return nil
+}
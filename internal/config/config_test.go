@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path := filepath.Join(dir, "sensors", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	body := "interval = \"5s\"\nhistory_size = 120\ndata_dir = \"/from/file\"\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SENSORS_DATA_DIR", "/from/env")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Interval != 5*time.Second {
+		t.Errorf("Interval: got %v, want 5s from file", cfg.Interval)
+	}
+	if cfg.HistorySize != 120 {
+		t.Errorf("HistorySize: got %d, want 120 from file", cfg.HistorySize)
+	}
+	if cfg.DataDir != "/from/env" {
+		t.Errorf("DataDir: got %q, want env to override file", cfg.DataDir)
+	}
+	if cfg.Webhook != "" {
+		t.Errorf("Webhook: got %q, want built-in default", cfg.Webhook)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path := filepath.Join(dir, "sensors", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	body := "[theme]\nwarm_fraction = 0.9\ncrit = \"#ff0000\"\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Theme.WarmFraction != 0.9 || cfg.Theme.Crit != "#ff0000" || cfg.Theme.Ok != "" {
+		t.Errorf("unexpected theme: %+v", cfg.Theme)
+	}
+
+	if err := os.WriteFile(path, []byte("[theme]\nwarm_fraction = 1.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("expected error for warm_fraction > 1")
+	}
+}
+
+func TestLoadThresholds(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path := filepath.Join(dir, "sensors", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	body := "[thresholds.\"HDD/SSD\"]\nhigh = 50\ncrit = 60\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Thresholds["HDD/SSD"]; got != (ComponentThresholds{High: 50, Crit: 60}) {
+		t.Errorf("HDD/SSD thresholds: got %+v", got)
+	}
+}
+
+func TestSaveHidden(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	// A missing file is created
+	if err := SaveHidden([]string{"acpitz-acpi-0/temp1"}); err != nil {
+		t.Fatalf("SaveHidden: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Hidden, []string{"acpitz-acpi-0/temp1"}) {
+		t.Errorf("Hidden: got %q", cfg.Hidden)
+	}
+
+	// An existing list is replaced in place, keeping comments and tables
+	path := filepath.Join(dir, "sensors", "config.toml")
+	body := "# my settings\ninterval = \"2s\"\nhidden = [\n  \"a/b\", # noisy\n  \"c/d\",\n]\n\n[theme]\nok = \"78\"\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveHidden([]string{"x/y", `quote"d/key`}); err != nil {
+		t.Fatalf("SaveHidden: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "# my settings\ninterval = \"2s\"\nhidden = [\"x/y\", \"quote\\\"d/key\"]\n\n[theme]\nok = \"78\"\n"
+	if string(data) != want {
+		t.Errorf("rewritten config:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Without an assignment the line goes before the first table
+	if err := os.WriteFile(path, []byte("[theme]\nok = \"78\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveHidden(nil); err != nil {
+		t.Fatalf("SaveHidden: %v", err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Hidden) != 0 || cfg.Theme.Ok != "78" {
+		t.Errorf("got Hidden %q, theme %+v", cfg.Hidden, cfg.Theme)
+	}
+}
+
+func TestLoadChipsBlankName(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	path := filepath.Join(dir, "sensors", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{"[chips]\nit87 = \"  \"\n", "[chips]\n\" \" = \"Board\"\n"} {
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %q", body)
+		}
+	}
+}
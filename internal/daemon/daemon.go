@@ -0,0 +1,143 @@
+// Package daemon polls sensors in the background and serves the latest
+// snapshot over a unix socket, so frequent callers such as `sensors status`
+// in a shell prompt don't spawn the sensor tools on every call.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// Snapshot is the latest poll result served to clients.
+type Snapshot struct {
+	Time     time.Time        `json:"time"`
+	Readings []sensor.Reading `json:"readings"`
+}
+
+// SocketPath returns $XDG_RUNTIME_DIR/sensors.sock, falling back to a
+// per-user socket in the system temp directory.
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "sensors.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sensors-%d.sock", os.Getuid()))
+}
+
+// Server polls with Read every Interval and answers each connection with
+// the latest snapshot as JSON.
+type Server struct {
+	Interval time.Duration
+	Read     func(ctx context.Context) ([]sensor.Reading, error)
+
+	mu        sync.RWMutex
+	latest    Snapshot
+	lastErr   string    // error of the last failed poll, kept for /healthz
+	lastErrAt time.Time // when it failed
+}
+
+// NewServer creates a server that reads all sensors every interval.
+func NewServer(interval time.Duration) *Server {
+	return &Server{Interval: interval, Read: sensor.ReadAllContext}
+}
+
+// Serve polls once, listens on path and serves until ctx is cancelled.
+// A stale socket left by a crashed daemon is removed; a live one is an
+// error.
+func (s *Server) Serve(ctx context.Context, path string) error {
+	if _, err := Query(path); err == nil {
+		return fmt.Errorf("daemon already running on %s", path)
+	}
+	os.Remove(path)
+
+	s.poll(ctx)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go s.pollLoop(ctx)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+func (s *Server) poll(ctx context.Context) {
+	readings, err := s.Read(ctx)
+	if err != nil {
+		s.mu.Lock()
+		s.lastErr, s.lastErrAt = err.Error(), time.Now()
+		s.mu.Unlock()
+		// Keep serving the previous snapshot unless some sources read
+		if len(readings) == 0 {
+			return
+		}
+	}
+	s.mu.Lock()
+	s.latest = Snapshot{Time: time.Now(), Readings: readings}
+	s.mu.Unlock()
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	s.mu.RLock()
+	snap := s.latest
+	s.mu.RUnlock()
+	conn.SetWriteDeadline(time.Now().Add(time.Second))
+	json.NewEncoder(conn).Encode(snap)
+}
+
+// queryTimeout keeps a missing or wedged daemon from slowing callers down.
+const queryTimeout = 200 * time.Millisecond
+
+// Query fetches the latest snapshot from a daemon listening on path.
+func Query(path string) (Snapshot, error) {
+	var snap Snapshot
+	conn, err := net.DialTimeout("unix", path, queryTimeout)
+	if err != nil {
+		return snap, err
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(queryTimeout))
+	if err := json.NewDecoder(conn).Decode(&snap); err != nil {
+		return snap, err
+	}
+	if snap.Time.IsZero() {
+		return snap, errors.New("daemon has no readings yet")
+	}
+	return snap, nil
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+func TestServeAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sensors.sock")
+	calls := 0
+	s := &Server{
+		Interval: time.Hour,
+		Read: func(ctx context.Context) ([]sensor.Reading, error) {
+			calls++
+			return []sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 55}}, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, path) }()
+
+	var snap Snapshot
+	var err error
+	for i := 0; i < 50; i++ {
+		if snap, err = Query(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(snap.Readings) != 1 || snap.Readings[0].Temp != 55 {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+
+	// Queries are served from the snapshot, not by re-reading
+	Query(path)
+	if calls != 1 {
+		t.Errorf("Read called %d times, want 1", calls)
+	}
+
+	// A second daemon on the same socket refuses to start
+	if err := (&Server{Interval: time.Hour, Read: s.Read}).Serve(ctx, path); err == nil {
+		t.Error("expected error starting a second daemon")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+	if _, err := Query(path); err == nil {
+		t.Error("expected Query to fail after shutdown")
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	var fail error
+	s := &Server{
+		Interval: time.Second,
+		Read: func(ctx context.Context) ([]sensor.Reading, error) {
+			return []sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 55}}, fail
+		},
+	}
+	get := func() (int, Health) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var h Health
+		if err := json.NewDecoder(rec.Body).Decode(&h); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec.Code, h
+	}
+
+	if code, h := get(); code != http.StatusServiceUnavailable || h.Reason == "" {
+		t.Errorf("before any poll: %d %+v, want 503 with a reason", code, h)
+	}
+
+	s.poll(context.Background())
+	if code, h := get(); code != http.StatusOK || h.Status != "ok" {
+		t.Errorf("after a poll: %d %+v, want 200", code, h)
+	}
+
+	// A failed poll keeps the last snapshot; health reports the error
+	fail = errors.New("sensors: exit status 1")
+	s.poll(context.Background())
+	h := s.Health(s.latest.Time.Add(1500 * time.Millisecond))
+	if h.Status != "ok" || h.LastError != "sensors: exit status 1" || h.LastPollAge != 1.5 {
+		t.Errorf("after a failed poll: %+v", h)
+	}
+	if h := s.Health(s.latest.Time.Add(3 * time.Second)); h.Status != "stale" || !strings.Contains(h.Reason, "3s ago") {
+		t.Errorf("3 intervals after the last poll: %+v, want stale", h)
+	}
+}
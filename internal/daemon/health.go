@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Health is the /healthz body. The daemon is healthy while its last
+// successful poll is at most two intervals old; a tool that hangs (e.g.
+// smartctl on a sleeping drive) blocks the poll loop and ages it.
+type Health struct {
+	Status      string    `json:"status"` // "ok" or "stale"
+	Reason      string    `json:"reason,omitempty"`
+	LastPoll    time.Time `json:"last_poll"`
+	LastPollAge float64   `json:"last_poll_age_seconds"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+}
+
+// Health reports whether the poll loop is keeping up at now.
+func (s *Server) Health(now time.Time) Health {
+	s.mu.RLock()
+	last, lastErr, errAt := s.latest.Time, s.lastErr, s.lastErrAt
+	s.mu.RUnlock()
+
+	h := Health{Status: "ok", LastPoll: last, LastError: lastErr, LastErrorAt: errAt}
+	if last.IsZero() {
+		h.Status, h.Reason = "stale", "no successful poll yet"
+		return h
+	}
+	age := now.Sub(last)
+	h.LastPollAge = age.Seconds()
+	if age > 2*s.Interval {
+		h.Status = "stale"
+		h.Reason = fmt.Sprintf("last successful poll %v ago, over twice the %v interval", age.Round(time.Second), s.Interval)
+	}
+	return h
+}
+
+// Handler serves GET /healthz: 200 with the Health body while healthy,
+// 503 otherwise, for orchestrators to restart a wedged daemon.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		h := s.Health(time.Now())
+		w.Header().Set("Content-Type", "application/json")
+		if h.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	})
+	return mux
+}
+
+// ListenHTTP serves Handler on addr until ctx is cancelled.
+func (s *Server) ListenHTTP(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
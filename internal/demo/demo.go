@@ -0,0 +1,117 @@
+// Package demo synthesizes plausible sensor history (daily temperature
+// curves with stress spikes) for developing and demoing the viewer
+// without real hardware. Output depends only on the seed and the day, so
+// it is stable across runs and machines.
+package demo
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+)
+
+// Sample is one poll's worth of readings.
+type Sample struct {
+	Time     time.Time
+	Readings []sensor.Reading
+}
+
+// profile shapes one synthetic sensor: its idle temperature, how far the
+// day's warmth lifts it, and how much each stress target heats it.
+type profile struct {
+	chip, adapter, label string
+	base, swing          float64
+	high, crit           float64
+	stress               map[string]float64 // °C added at full load, by stress target
+}
+
+var profiles = []profile{
+	{"coretemp-isa-0000", "ISA adapter", "Package id 0", 42, 8, 80, 100, map[string]float64{"cpu": 38, "all": 40}},
+	{"coretemp-isa-0000", "ISA adapter", "Core 0", 40, 8, 80, 100, map[string]float64{"cpu": 36, "all": 38}},
+	{"nvme-pci-0300", "PCI adapter", "Composite", 38, 5, 81.8, 84.8, map[string]float64{"nvme": 22, "all": 24}},
+	{"nvidia-gpu-0", "NVIDIA GeForce RTX 3090", "GPU Temp", 35, 6, 93, 98, map[string]float64{"gpu": 40, "all": 40}},
+}
+
+var targets = []string{"cpu", "gpu", "nvme", "all"}
+
+const (
+	runsPerDay = 3
+	heatTau    = 30 * time.Second // time constant of warming under load
+	coolTau    = 90 * time.Second // and of cooling afterwards
+)
+
+// run is one synthetic stress test.
+type run struct {
+	target      string
+	start, stop time.Time
+}
+
+// Day returns the samples for the calendar day containing day, one every
+// interval from midnight in day's location, and the stress start/stop
+// events that explain its spikes.
+func Day(day time.Time, interval time.Duration, seed int64) ([]Sample, []store.Event) {
+	y, mo, d := day.Date()
+	midnight := time.Date(y, mo, d, 0, 0, 0, 0, day.Location())
+	next := time.Date(y, mo, d+1, 0, 0, 0, 0, day.Location())
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(y*10000+int(mo)*100+d)))
+
+	var runs []run
+	var events []store.Event
+	for i := 0; i < runsPerDay; i++ {
+		// Spread the runs over the working day, one per 4 hour slot
+		slot := midnight.Add(time.Duration(8+4*i) * time.Hour)
+		start := slot.Add(time.Duration(rng.IntN(180)) * time.Minute)
+		r := run{
+			target: targets[rng.IntN(len(targets))],
+			start:  start,
+			stop:   start.Add(time.Duration(5+rng.IntN(16)) * time.Minute),
+		}
+		runs = append(runs, r)
+		events = append(events,
+			store.Event{Time: r.start, Label: r.target + " stress start"},
+			store.Event{Time: r.stop, Label: r.target + " stress stop"})
+	}
+
+	var samples []Sample
+	for t := midnight; t.Before(next); t = t.Add(interval) {
+		// Warmest mid-afternoon, coolest before dawn
+		hour := t.Sub(midnight).Hours()
+		diurnal := math.Sin(2 * math.Pi * (hour - 9) / 24)
+
+		readings := make([]sensor.Reading, 0, len(profiles))
+		for _, p := range profiles {
+			temp := p.base + p.swing*diurnal + rng.NormFloat64()*0.4
+			for _, r := range runs {
+				temp += p.stress[r.target] * load(r, t)
+			}
+			readings = append(readings, sensor.Reading{
+				Chip:    p.chip,
+				Adapter: p.adapter,
+				Label:   p.label,
+				Temp:    math.Round(temp*10) / 10,
+				High:    p.high,
+				Crit:    p.crit,
+				HasHigh: true,
+				HasCrit: true,
+			})
+		}
+		samples = append(samples, Sample{Time: t, Readings: readings})
+	}
+	return samples, events
+}
+
+// load is the fraction of a run's full heating felt at t: rising towards
+// 1 while it runs and decaying once it stops.
+func load(r run, t time.Time) float64 {
+	if t.Before(r.start) {
+		return 0
+	}
+	if !t.After(r.stop) {
+		return 1 - math.Exp(-float64(t.Sub(r.start))/float64(heatTau))
+	}
+	peak := 1 - math.Exp(-float64(r.stop.Sub(r.start))/float64(heatTau))
+	return peak * math.Exp(-float64(t.Sub(r.stop))/float64(coolTau))
+}
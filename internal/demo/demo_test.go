@@ -0,0 +1,60 @@
+package demo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDayDeterministic(t *testing.T) {
+	day := time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC)
+
+	a, aEvents := Day(day, time.Minute, 1)
+	b, bEvents := Day(day, time.Minute, 1)
+	if !reflect.DeepEqual(a, b) || !reflect.DeepEqual(aEvents, bEvents) {
+		t.Fatal("same seed and day should give the same data")
+	}
+	if len(a) != 24*60 {
+		t.Fatalf("expected one sample per minute, got %d", len(a))
+	}
+	if len(aEvents) != 2*runsPerDay {
+		t.Fatalf("expected a start and stop event per run, got %d", len(aEvents))
+	}
+
+	c, _ := Day(day, time.Minute, 2)
+	if reflect.DeepEqual(a, c) {
+		t.Error("a different seed should give different data")
+	}
+	next, _ := Day(day.AddDate(0, 0, 1), time.Minute, 1)
+	if reflect.DeepEqual(a[0].Readings, next[0].Readings) {
+		t.Error("consecutive days should differ")
+	}
+}
+
+func TestDayStressSpike(t *testing.T) {
+	day := time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC)
+	samples, events := Day(day, 10*time.Second, 1)
+
+	// Late in the first run, every sensor it targets runs well above idle
+	stop := events[1].Time
+	var idle, loaded float64
+	for _, s := range samples {
+		switch {
+		case s.Time.Equal(events[0].Time):
+			idle = maxTemp(s)
+		case s.Time.Equal(stop.Truncate(10 * time.Second)):
+			loaded = maxTemp(s)
+		}
+	}
+	if loaded < idle+10 {
+		t.Errorf("expected a stress spike: %.1f at start, %.1f before stop (%s)", idle, loaded, events[0].Label)
+	}
+}
+
+func maxTemp(s Sample) float64 {
+	hottest := s.Readings[0].Temp
+	for _, r := range s.Readings {
+		hottest = max(hottest, r.Temp)
+	}
+	return hottest
+}
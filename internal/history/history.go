@@ -4,13 +4,15 @@ package history
 
 import (
 	"math"
+	"sync"
 	"time"
 )
 
 // Point is a single data point in the temperature history.
 type Point struct {
-	Temp float64
-	Time time.Time
+	Temp    float64
+	Time    time.Time
+	Suspect bool // outlier kept for the chart but left out of Min/Peak/Avg
 }
 
 // Buffer stores a ring buffer of temperature readings for one sensor.
@@ -19,8 +21,20 @@ type Buffer struct {
 	Max    int // capacity
 	Min    float64
 	Peak   float64
+
+	// OutlierDelta flags readings further than this many degrees from
+	// the recent average as suspect; 0 disables outlier rejection.
+	OutlierDelta float64
 }
 
+// outlierWindow is how many recent trusted points the outlier check
+// averages over, and outlierRun how many consecutive suspect readings
+// that agree with each other are taken as a real level change.
+const (
+	outlierWindow = 10
+	outlierRun    = 3
+)
+
 // NewBuffer creates a new history ring buffer with the given capacity.
 func NewBuffer(capacity int) *Buffer {
 	return &Buffer{
@@ -31,9 +45,11 @@ func NewBuffer(capacity int) *Buffer {
 	}
 }
 
-// Push adds a new temperature reading to the history.
+// Push adds a new temperature reading to the history. With OutlierDelta
+// set, a reading that jumps away from the recent average is stored as
+// suspect and does not move Min or Peak.
 func (b *Buffer) Push(temp float64, t time.Time) {
-	p := Point{Temp: temp, Time: t}
+	p := Point{Temp: temp, Time: t, Suspect: b.isOutlier(temp)}
 	if len(b.Points) >= b.Max {
 		copy(b.Points, b.Points[1:])
 		b.Points[len(b.Points)-1] = p
@@ -41,6 +57,9 @@ func (b *Buffer) Push(temp float64, t time.Time) {
 		b.Points = append(b.Points, p)
 	}
 
+	if p.Suspect {
+		return
+	}
 	if temp < b.Min {
 		b.Min = temp
 	}
@@ -49,6 +68,50 @@ func (b *Buffer) Push(temp float64, t time.Time) {
 	}
 }
 
+// isOutlier reports whether temp deviates more than OutlierDelta from the
+// average of the last few trusted points. A run of suspect readings close
+// to each other is a real change (a load step, not a glitch), so it ends
+// the run instead of being flagged forever.
+func (b *Buffer) isOutlier(temp float64) bool {
+	if b.OutlierDelta <= 0 {
+		return false
+	}
+
+	run := 0
+	for i := len(b.Points) - 1; i >= 0 && b.Points[i].Suspect; i-- {
+		run++
+	}
+	if run >= outlierRun-1 && math.Abs(temp-b.Points[len(b.Points)-1].Temp) <= b.OutlierDelta {
+		return false
+	}
+
+	sum, n := 0.0, 0
+	for i := len(b.Points) - 1; i >= 0 && n < outlierWindow; i-- {
+		if !b.Points[i].Suspect {
+			sum += b.Points[i].Temp
+			n++
+		}
+	}
+	if n == 0 {
+		return false
+	}
+	return math.Abs(temp-sum/float64(n)) > b.OutlierDelta
+}
+
+// ResetStats restarts Min and Peak from the most recent trusted reading,
+// keeping the stored points.
+func (b *Buffer) ResetStats() {
+	b.Min = math.MaxFloat64
+	b.Peak = -math.MaxFloat64
+	for i := len(b.Points) - 1; i >= 0; i-- {
+		if !b.Points[i].Suspect {
+			b.Min = b.Points[i].Temp
+			b.Peak = b.Points[i].Temp
+			break
+		}
+	}
+}
+
 // Last returns the most recent temperature, or 0 if empty.
 func (b *Buffer) Last() float64 {
 	if len(b.Points) == 0 {
@@ -57,16 +120,52 @@ func (b *Buffer) Last() float64 {
 	return b.Points[len(b.Points)-1].Temp
 }
 
-// Avg returns the average temperature across all stored points.
+// Avg returns the average temperature across all stored points, leaving
+// out suspect ones.
 func (b *Buffer) Avg() float64 {
-	if len(b.Points) == 0 {
+	sum, n := 0.0, 0
+	for _, p := range b.Points {
+		if !p.Suspect {
+			sum += p.Temp
+			n++
+		}
+	}
+	if n == 0 {
 		return 0
 	}
-	sum := 0.0
-	for _, p := range b.Points {
-		sum += p.Temp
+	return sum / float64(n)
+}
+
+// rateWindow is how many recent points Rate fits a slope through.
+const rateWindow = 5
+
+// Rate returns the current rate of change in degrees per second, as the
+// least-squares slope over the last few points. It returns 0 with fewer
+// than two points or when they share a timestamp.
+func (b *Buffer) Rate() float64 {
+	pts := b.Points
+	if len(pts) > rateWindow {
+		pts = pts[len(pts)-rateWindow:]
+	}
+	if len(pts) < 2 {
+		return 0
 	}
-	return sum / float64(len(b.Points))
+
+	t0 := pts[0].Time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range pts {
+		x := p.Time.Sub(t0).Seconds()
+		sumX += x
+		sumY += p.Temp
+		sumXY += x * p.Temp
+		sumXX += x * x
+	}
+	n := float64(len(pts))
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
 }
 
 // LastN returns the last n temperature values (for chart rendering).
@@ -99,10 +198,26 @@ func (b *Buffer) LastNPoints(n int) []Point {
 	return out
 }
 
+// Clone returns a deep copy of the buffer.
+func (b *Buffer) Clone() *Buffer {
+	c := *b
+	c.Points = make([]Point, len(b.Points), b.Max)
+	copy(c.Points, b.Points)
+	return &c
+}
+
 // Store manages histories for all sensors.
+//
+// Record and Get are safe to call from multiple goroutines, but the
+// *Buffer returned by Get is live and keeps changing as readings are
+// recorded. Goroutines other than the one calling Record should read
+// through Snapshot instead.
 type Store struct {
-	Data     map[string]*Buffer
-	Capacity int
+	Data         map[string]*Buffer
+	Capacity     int
+	OutlierDelta float64 // passed to new buffers; 0 disables outlier rejection
+
+	mu sync.RWMutex
 }
 
 // NewStore creates a new store with the given per-sensor capacity.
@@ -113,17 +228,62 @@ func NewStore(capacity int) *Store {
 	}
 }
 
-// Record adds a reading for the given sensor key.
-func (s *Store) Record(key string, temp float64, t time.Time) {
+// Record adds a reading for the given sensor key and reports whether it
+// was marked Suspect by the outlier filter.
+func (s *Store) Record(key string, temp float64, t time.Time) (suspect bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	b, ok := s.Data[key]
 	if !ok {
 		b = NewBuffer(s.Capacity)
+		b.OutlierDelta = s.OutlierDelta
 		s.Data[key] = b
 	}
 	b.Push(temp, t)
+	return b.Points[len(b.Points)-1].Suspect
 }
 
 // Get returns the history buffer for a sensor key, or nil.
 func (s *Store) Get(key string) *Buffer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.Data[key]
 }
+
+// Delete drops a sensor's buffer, e.g. once its hardware is gone.
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Data, key)
+}
+
+// Prune drops the buffers whose newest point is older than maxAge at now,
+// so keys of sensors that stopped reporting do not pile up over a long
+// session. It returns the dropped keys.
+func (s *Store) Prune(maxAge time.Duration, now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var dropped []string
+	for key, b := range s.Data {
+		if len(b.Points) == 0 || now.Sub(b.Points[len(b.Points)-1].Time) > maxAge {
+			delete(s.Data, key)
+			dropped = append(dropped, key)
+		}
+	}
+	return dropped
+}
+
+// Snapshot returns a deep copy of every buffer, safe to read while
+// other goroutines keep recording.
+func (s *Store) Snapshot() map[string]*Buffer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]*Buffer, len(s.Data))
+	for key, b := range s.Data {
+		out[key] = b.Clone()
+	}
+	return out
+}
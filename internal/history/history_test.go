@@ -1,6 +1,8 @@
 package history
 
 import (
+	"math"
+	"sync"
 	"testing"
 	"time"
 )
@@ -59,3 +61,159 @@ func TestLastNPoints(t *testing.T) {
 		t.Errorf("last point time: got %v, want %v", last.Time, base.Add(119*time.Second))
 	}
 }
+
+// TestStoreConcurrentAccess is meant to be run with -race.
+func TestStoreConcurrentAccess(t *testing.T) {
+	s := NewStore(50)
+	keys := []string{"coretemp/Core 0", "coretemp/Core 1", "nvme/Composite"}
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			for _, k := range keys {
+				s.Record(k, float64(40+i%10), base.Add(time.Duration(i)*time.Second))
+			}
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s.Get(keys[i%len(keys)])
+				for _, b := range s.Snapshot() {
+					b.Avg()
+					b.LastNPoints(10)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap := s.Snapshot()
+	if len(snap) != len(keys) {
+		t.Fatalf("expected %d buffers, got %d", len(keys), len(snap))
+	}
+	for _, k := range keys {
+		if n := len(snap[k].Points); n != 50 {
+			t.Errorf("%s: expected 50 points, got %d", k, n)
+		}
+	}
+
+	// Snapshots are copies: further records must not leak into them
+	s.Record(keys[0], 99, base.Add(time.Hour))
+	if snap[keys[0]].Last() == 99 {
+		t.Error("snapshot changed after Record")
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	s := NewStore(10)
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	s.Record("nvme/Composite", 40, base)
+	s.Record("coretemp/Core 0", 50, base)
+
+	// The drive is unplugged; only the CPU keeps reporting
+	for i := 1; i <= 10; i++ {
+		s.Record("coretemp/Core 0", 50, base.Add(time.Duration(i)*time.Minute))
+	}
+	now := base.Add(10 * time.Minute)
+	if dropped := s.Prune(15*time.Minute, now); len(dropped) != 0 {
+		t.Errorf("pruned %q before maxAge", dropped)
+	}
+	dropped := s.Prune(5*time.Minute, now)
+	if len(dropped) != 1 || dropped[0] != "nvme/Composite" {
+		t.Errorf("dropped %q, want [nvme/Composite]", dropped)
+	}
+	if s.Get("nvme/Composite") != nil {
+		t.Error("stale buffer still in the store")
+	}
+	if s.Get("coretemp/Core 0") == nil {
+		t.Error("live buffer was pruned")
+	}
+}
+
+func TestRate(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+
+	h := NewBuffer(100)
+	if h.Rate() != 0 {
+		t.Errorf("empty Rate: got %f, want 0", h.Rate())
+	}
+
+	// Steady for a while, then +6°C per second over the last 5 points
+	for i := 0; i < 10; i++ {
+		h.Push(40, base.Add(time.Duration(i)*time.Second))
+	}
+	for i := 1; i <= 5; i++ {
+		h.Push(40+6*float64(i), base.Add(time.Duration(9+i)*time.Second))
+	}
+	if got := h.Rate(); math.Abs(got-6) > 1e-9 {
+		t.Errorf("Rate: got %f, want 6", got)
+	}
+
+	// Sub-second sampling still yields per-second rates
+	fast := NewBuffer(10)
+	for i := 0; i < 5; i++ {
+		fast.Push(50-0.2*float64(i), base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	if got := fast.Rate(); math.Abs(got+2) > 1e-9 {
+		t.Errorf("fast Rate: got %f, want -2", got)
+	}
+
+	// Identical timestamps have no defined slope
+	same := NewBuffer(10)
+	same.Push(40, base)
+	same.Push(50, base)
+	if same.Rate() != 0 {
+		t.Errorf("same-time Rate: got %f, want 0", same.Rate())
+	}
+}
+
+func TestOutlierRejection(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	at := func(i int) time.Time { return base.Add(time.Duration(i) * time.Second) }
+
+	// Off by default: a glitch moves the peak
+	off := NewBuffer(60)
+	for i := 0; i < 10; i++ {
+		off.Push(50, at(i))
+	}
+	off.Push(255, at(10))
+	if off.Peak != 255 {
+		t.Errorf("disabled: Peak %f, want 255", off.Peak)
+	}
+
+	h := NewBuffer(60)
+	h.OutlierDelta = 20
+	for i := 0; i < 10; i++ {
+		h.Push(50+float64(i%3), at(i))
+	}
+	h.Push(255, at(10))
+	h.Push(0, at(11))
+	h.Push(51, at(12))
+	if h.Peak != 52 || h.Min != 50 {
+		t.Errorf("glitches moved stats: min %f peak %f", h.Min, h.Peak)
+	}
+	if !h.Points[10].Suspect || !h.Points[11].Suspect || h.Points[12].Suspect {
+		t.Errorf("suspect flags: %v %v %v", h.Points[10].Suspect, h.Points[11].Suspect, h.Points[12].Suspect)
+	}
+	if len(h.Points) != 13 {
+		t.Errorf("suspect readings not recorded: %d points", len(h.Points))
+	}
+	if avg := h.Avg(); avg < 50 || avg > 52 {
+		t.Errorf("Avg includes suspect readings: %f", avg)
+	}
+
+	// A sustained step is a real change: the first readings are suspect,
+	// then the run is accepted
+	for i := 0; i < 5; i++ {
+		h.Push(90, at(13+i))
+	}
+	if h.Peak != 90 {
+		t.Errorf("sustained step not accepted: Peak %f", h.Peak)
+	}
+}
@@ -3,24 +3,47 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/luki/sensors/internal/alert"
 	"github.com/luki/sensors/internal/chart"
+	"github.com/luki/sensors/internal/clipboard"
 	"github.com/luki/sensors/internal/history"
 	"github.com/luki/sensors/internal/sensor"
 	"github.com/luki/sensors/internal/store"
 )
 
 const (
-	pollInterval = 1 * time.Second
-	historySize  = 600 // 10 minutes at 1s interval
+	pollInterval  = 1 * time.Second // default when Options.Interval is unset
+	historySize   = 600             // 10 minutes at 1s interval
+	expirePolls   = 30              // default polls a sensor may be missing before it is dropped
+	pruneEvery    = 60              // polls between sweeps of stale history buffers
+	detailHeight  = 8               // rows of the T detail chart
+	minChartWidth = 15              // narrowest useful sparkline
+	fullRowWidth  = 75              // row width besides the sparkline with stats
+	allPeakWidth  = 9               // " all" plus the all-time peak value
+	sparkRowWidth = 41              // row width besides the sparkline without stats
+	minPanelWidth = 110             // narrowest panel in the multi-column layout
+	maxColumns    = 3               // most panels placed side by side
+	compactWidth  = 48              // narrowest panel in the compact layout
+	compactCols   = 6               // most compact panels placed side by side
+	maxChartWidth = 140             // widest sparkline, also the peak-hold depth
+	maxChipWidth  = 20              // widest chip ID shown per row when grouped
+	minValueWidth = 7               // value column fits "100.0°C"; wider values widen it
+	warmRate      = 1.0             // °C/s shown in warn color
+	spikeRate     = 5.0             // °C/s flagged as a spike (fan failure, sudden load)
 )
 
 // ── Messages ─────────────────────────────────────────────────────────
@@ -30,12 +53,29 @@ type tickMsg time.Time
 type sensorDataMsg struct {
 	readings []sensor.Reading
 	time     time.Time
+	failed   []*sensor.SourceError // sources that failed while others read
 }
 
 type errMsg struct{ err error }
 
 func (e errMsg) Error() string { return e.err.Error() }
 
+type webhookErrMsg struct{ err error }
+
+// hookMsg reports a finished on-crit command.
+type hookMsg struct {
+	e   alert.Event
+	err error
+}
+
+// copiedMsg reports a finished clipboard copy; text is empty when no
+// clipboard tool was available.
+type copiedMsg struct{ text string }
+
+// now is the clock the title bar's uptime is measured with, replaced in
+// tests for stable output.
+var now = time.Now
+
 // ── Model ────────────────────────────────────────────────────────────
 
 // Model is the BubbleTea model for the live monitor.
@@ -51,64 +91,303 @@ type Model struct {
 	lastPoll  time.Time
 	startTime time.Time
 	paused    bool
+	interval  time.Duration
+	status    string // transient message shown in the title bar
+	detector  *alert.Detector
+	webhook   *alert.Webhook
+	onCrit    *alert.CritHook
+	ctx       context.Context // cancelled on quit to kill in-flight reads
+	cancel    context.CancelFunc
+	peakHold  bool
+	holds     map[string][]float64 // per-sensor column peaks, newest column first
+	grouped   bool                 // panels per component (FriendlyName) instead of per chip
+	peaks     store.Peaks          // all-time peaks, saved to peaks.json on quit
+	panels    *panelCache          // rendered panels, reused while their inputs are unchanged
+	session   store.Peaks          // peaks since startTime, for Summary
+	breaches  []alert.Event        // crit crossings since startTime, for Summary
+	hidden    map[string]bool      // keys not rendered, still polled and recorded
+	selecting bool                 // a row is selected for per-sensor actions
+	selected  int                  // index of the selected row in visibleRows
+	saveHide  func([]string) error // saves the hidden keys; nil when not configured
+	pinned    []string             // keys shown first in a sticky panel, in pin order
+	savePins  func([]string) error // saves the pinned keys; nil when not configured
+	searching bool                 // typing a / search for a sensor to select
+	query     string               // the / search text
+	polls     int                  // sensor polls received
+	seen      map[string]int       // poll number each key was last read in
+	expire    int                  // polls a key may be missing before it is dropped
+	detail    bool                 // T: tall chart with limit lines under the selected row
+	sigma     float64              // standard deviations above its panel's mean that mark a sensor; 0 disables
+	compact   bool                 // v: one tight line per sensor, no sparkline or stats
+	failing   []string             // "source: error" per source that failed the last poll
+	gone      map[string]bool      // expired keys kept in order for their session peak
+}
+
+// Options configures optional monitor behavior.
+type Options struct {
+	Interval     time.Duration             // poll interval; 0 uses pollInterval
+	HistorySize  int                       // points kept per sensor; 0 uses historySize
+	Webhook      string                    // URL to POST crit alerts to; empty disables
+	OnCrit       string                    // shell command run when a sensor stays above crit; empty disables
+	CritSustain  time.Duration             // how long a sensor must stay above crit before OnCrit runs
+	OutlierDelta float64                   // °C off the recent average that marks a reading suspect; 0 disables
+	Hidden       []string                  // chip/label keys not to render
+	SaveHidden   func(keys []string) error // persists the hidden keys after each x/X; nil keeps them for the session
+	Pinned       []string                  // chip/label keys shown first, in order
+	SavePinned   func(keys []string) error // persists the pinned keys after each t; nil keeps them for the session
+	ExpirePolls  int                       // polls a sensor may be missing before it is dropped; 0 uses expirePolls
+	CohortSigma  float64                   // standard deviations above its panel's mean that mark a sensor; 0 disables
+	Compact      bool                      // start in the compact value-only layout
 }
 
 // New creates the initial model for the live monitor.
-func New() Model {
+func New(opts Options) Model {
+	if opts.Interval <= 0 {
+		opts.Interval = pollInterval
+	}
+	if opts.HistorySize <= 0 {
+		opts.HistorySize = historySize
+	}
+	if opts.ExpirePolls <= 0 {
+		opts.ExpirePolls = expirePolls
+	}
+
 	ds, err := store.New()
+	ctx, cancel := context.WithCancel(context.Background())
 	m := Model{
-		history:   history.NewStore(historySize),
+		history:   history.NewStore(opts.HistorySize),
 		store:     ds,
 		startTime: time.Now(),
+		interval:  opts.Interval,
+		detector:  alert.NewDetector(),
+		ctx:       ctx,
+		cancel:    cancel,
+		panels:    newPanelCache(),
+		session:   store.Peaks{},
+		hidden:    make(map[string]bool, len(opts.Hidden)),
+		saveHide:  opts.SaveHidden,
+		pinned:    slices.Clone(opts.Pinned),
+		savePins:  opts.SavePinned,
+		seen:      make(map[string]int),
+		expire:    opts.ExpirePolls,
+		sigma:     opts.CohortSigma,
+		compact:   opts.Compact,
+	}
+	for _, key := range opts.Hidden {
+		m.hidden[key] = true
 	}
 	if err != nil {
 		m.err = fmt.Errorf("disk store: %w", err)
 	}
+	if opts.Webhook != "" {
+		m.webhook = alert.NewWebhook(opts.Webhook)
+	}
+	if opts.OnCrit != "" {
+		m.onCrit = alert.NewCritHook(opts.OnCrit, opts.CritSustain)
+	}
+	m.history.OutlierDelta = opts.OutlierDelta
+	peaks, err := store.LoadPeaks()
+	if err != nil && m.err == nil {
+		m.err = fmt.Errorf("peaks: %w", err)
+	}
+	m.peaks = peaks
+	m.preloadHistory()
 	return m
 }
 
+// preloadHistory seeds the ring buffers from today's CSV so the sparklines
+// show recent context immediately instead of starting empty.
+func (m *Model) preloadHistory() {
+	recent, err := store.LoadRecent(m.history.Capacity)
+	if err != nil {
+		return
+	}
+	for key, rs := range recent {
+		for _, r := range rs {
+			m.history.Record(key, r.Temp, r.Time)
+		}
+	}
+}
+
 // ── Commands ─────────────────────────────────────────────────────────
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(pollInterval, func(t time.Time) tea.Msg {
+func tickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-func pollSensors() tea.Msg {
-	readings, err := sensor.ReadAll()
-	if err != nil {
-		return errMsg{err}
+func sendWebhook(w *alert.Webhook, e alert.Event) tea.Cmd {
+	return func() tea.Msg {
+		if err := w.Send(e); err != nil {
+			return webhookErrMsg{err}
+		}
+		return nil
+	}
+}
+
+func runHook(h *alert.CritHook, e alert.Event) tea.Cmd {
+	return func() tea.Msg {
+		return hookMsg{e, h.Run(e)}
+	}
+}
+
+// copyReading copies text to the clipboard, reporting nothing when no
+// clipboard tool is installed.
+func copyReading(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.Copy(text); err != nil {
+			return copiedMsg{}
+		}
+		return copiedMsg{text: text}
+	}
+}
+
+func pollSensors(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		readings, err := sensor.ReadAllContext(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Only a poll where every source failed is an error; otherwise
+		// the failures are warnings beside the sensors that did read
+		if err != nil && len(readings) == 0 {
+			return errMsg{err}
+		}
+		return sensorDataMsg{readings: readings, time: time.Now(), failed: sensor.SourceErrors(err)}
 	}
-	return sensorDataMsg{readings: readings, time: time.Now()}
 }
 
 // ── Init / Update ────────────────────────────────────────────────────
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(pollSensors, tickCmd())
+	return tea.Batch(pollSensors(m.ctx), tickCmd(m.interval))
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
+		if m.searching {
+			m.updateSearch(msg)
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.cancel()
 			if m.store != nil {
 				m.store.Close()
 			}
+			if m.peaks != nil {
+				if err := store.SavePeaks(m.peaks); err != nil {
+					m.err = fmt.Errorf("saving peaks: %w", err)
+				}
+			}
 			return m, tea.Quit
-		case "up", "k":
+		case "k":
 			if m.scroll > 0 {
 				m.scroll--
 			}
-		case "down", "j":
+		case "j":
 			m.scroll++
 		case "home":
 			m.scroll = 0
+			if m.selecting {
+				m.selected = 0
+			}
+		case "up", "down", "left", "right":
+			m.moveSelection(msg.String())
 		case " ", "p":
 			m.paused = !m.paused
+		case "T":
+			m.panels.reset()
+			m.detail = !m.detail
+			if m.detail && !m.selecting {
+				m.moveSelection("down")
+			}
+		case "H":
+			m.panels.reset()
+			m.peakHold = !m.peakHold
+			m.holds = make(map[string][]float64)
+			if m.peakHold {
+				m.updateHolds()
+			}
+		case "g":
+			m.grouped = !m.grouped
+			m.scroll = 0
+		case "v":
+			m.compact = !m.compact
+			m.scroll = 0
+		case "r":
+			m.panels.reset()
+			for _, key := range m.order {
+				if hist := m.history.Get(key); hist != nil {
+					hist.ResetStats()
+				}
+			}
+			m.holds = make(map[string][]float64)
+			m.updateHolds()
+			m.status = "stats reset"
+		case "y":
+			if r, ok := hottest(m.readings); ok {
+				return m, copyReading(fmt.Sprintf("%s %s: %.1f°C", r.Chip, r.Label, r.Temp))
+			}
+		case "tab":
+			m.moveSelection("down")
+		case "shift+tab":
+			m.moveSelection("up")
+		case "esc":
+			m.selecting = false
+		case "x":
+			r, ok := m.selectedReading()
+			if !ok {
+				m.status = "select a sensor with tab first"
+				break
+			}
+			if m.hidden == nil {
+				m.hidden = make(map[string]bool)
+			}
+			m.hidden[r.Key()] = true
+			m.selected = min(m.selected, len(m.visibleRows())-1)
+			m.selecting = m.selected >= 0
+			m.status = "hid " + r.Key()
+			m.persistHidden()
+		case "t":
+			r, ok := m.selectedReading()
+			if !ok {
+				m.status = "select a sensor with the arrow keys or / first"
+				break
+			}
+			if i := slices.Index(m.pinned, r.Key()); i >= 0 {
+				m.pinned = slices.Delete(m.pinned, i, i+1)
+				m.status = "unpinned " + r.Key()
+			} else {
+				m.pinned = append(m.pinned, r.Key())
+				m.status = "pinned " + r.Key()
+			}
+			m.panels.reset()
+			m.selectKey(r.Key())
+			if m.savePins != nil {
+				if err := m.savePins(slices.Clone(m.pinned)); err != nil {
+					m.err = fmt.Errorf("saving pinned sensors: %w", err)
+				}
+			}
+		case "/":
+			m.searching, m.query = true, ""
+		case "X":
+			if len(m.hidden) == 0 {
+				break
+			}
+			m.status = fmt.Sprintf("showing %d hidden", len(m.hidden))
+			clear(m.hidden)
+			m.persistHidden()
+		case "P":
+			path, err := m.exportSVG(time.Now())
+			if err != nil {
+				m.err = fmt.Errorf("export: %w", err)
+			} else {
+				m.status = "saved " + path
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -117,17 +396,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		if m.paused {
-			return m, tickCmd()
+			return m, tickCmd(m.interval)
 		}
-		return m, tea.Batch(pollSensors, tickCmd())
+		return m, tea.Batch(pollSensors(m.ctx), tickCmd(m.interval))
 
 	case sensorDataMsg:
 		m.readings = msg.readings
 		m.lastPoll = msg.time
+		m.failing = nil
+		for _, e := range msg.failed {
+			m.failing = append(m.failing, e.Error())
+		}
 		for _, r := range msg.readings {
-			m.history.Record(r.Key(), r.Temp, msg.time)
+			suspect := m.history.Record(r.Key(), r.Temp, msg.time)
+			// A glitch must not become a peak saved to peaks.json
+			if r.Unit != "" || suspect {
+				continue
+			}
+			if m.peaks != nil {
+				m.peaks.Observe(r.Key(), r.Temp, msg.time)
+			}
+			if m.session != nil {
+				m.session.Observe(r.Key(), r.Temp, msg.time)
+			}
 		}
 		m.order = buildOrder(m.readings, m.order)
+		m.expireMissing()
+		if m.polls%pruneEvery == 0 {
+			m.pruneHistory(msg.time)
+		}
+		m.updateHolds()
 
 		if m.store != nil {
 			if err := m.store.Write(msg.readings, msg.time); err != nil {
@@ -135,14 +433,419 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		var cmds []tea.Cmd
+		for _, e := range m.detector.Update(msg.readings, msg.time) {
+			m.breaches = append(m.breaches, e)
+			if m.webhook != nil && m.webhook.Allow(e) {
+				cmds = append(cmds, sendWebhook(m.webhook, e))
+			}
+		}
+		if m.onCrit != nil {
+			for _, e := range m.onCrit.Update(msg.readings, msg.time) {
+				cmds = append(cmds, runHook(m.onCrit, e))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case webhookErrMsg:
+		m.err = fmt.Errorf("webhook: %w", msg.err)
+
+	case hookMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("on-crit for %s: %w", msg.e.Key, msg.err)
+		} else {
+			m.status = "on-crit ran for " + msg.e.Key
+		}
+
+	case copiedMsg:
+		if msg.text != "" {
+			m.status = "copied " + msg.text
+		}
+
 	case errMsg:
 		m.err = msg.err
-		return m, tickCmd()
+		return m, tickCmd(m.interval)
 	}
 
 	return m, nil
 }
 
+// expireMissing drops sensors that have not been read for the last
+// expire polls, e.g. an unplugged drive, along with their history and
+// peak-hold columns. Pins and hidden keys are kept in case the hardware
+// comes back, and so is a session peak, so the exit summary still lists it.
+func (m *Model) expireMissing() {
+	if m.seen == nil {
+		m.seen = make(map[string]int)
+	}
+	m.polls++
+	for _, r := range m.readings {
+		m.seen[r.Key()] = m.polls
+		delete(m.gone, r.Key())
+	}
+	if m.expire <= 0 {
+		return
+	}
+	m.order = slices.DeleteFunc(m.order, func(key string) bool {
+		// Already expired and kept for Summary; nothing left to drop
+		if m.gone[key] || m.polls-m.seen[key] < m.expire {
+			return false
+		}
+		delete(m.seen, key)
+		delete(m.holds, key)
+		m.history.Delete(key)
+		m.panels.reset()
+		if _, peaked := m.session[key]; peaked {
+			if m.gone == nil {
+				m.gone = make(map[string]bool)
+			}
+			m.gone[key] = true
+			return false
+		}
+		return true
+	})
+}
+
+// pruneHistory drops history buffers that have had no point for a whole
+// chart's worth of polls. This catches keys expireMissing never tracked,
+// such as sensors preloaded from today's CSV that are no longer present.
+func (m *Model) pruneHistory(now time.Time) {
+	maxAge := time.Duration(m.history.Capacity) * m.interval
+	for _, key := range m.history.Prune(maxAge, now) {
+		delete(m.holds, key)
+	}
+}
+
+// persistHidden saves the hidden keys, sorted so the config file stays
+// stable between saves.
+func (m *Model) persistHidden() {
+	if m.saveHide == nil {
+		return
+	}
+	keys := make([]string, 0, len(m.hidden))
+	for key := range m.hidden {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if err := m.saveHide(keys); err != nil {
+		m.err = fmt.Errorf("saving hidden sensors: %w", err)
+	}
+}
+
+// moveSelection moves the selection cursor: up and down by row, wrapping
+// around, left and right to the first row of the previous or next panel.
+// The first move selects the first row. The view scrolls to keep the
+// selected row on screen.
+func (m *Model) moveSelection(dir string) {
+	order, groups := m.panelGroups()
+	var starts []int // index of each panel's first row
+	n := 0
+	for _, key := range order {
+		starts = append(starts, n)
+		n += len(groups[key].readings)
+	}
+	if n == 0 {
+		return
+	}
+	if !m.selecting {
+		m.selecting, m.selected = true, 0
+		m.scrollToSelection()
+		return
+	}
+
+	m.selected = min(m.selected, n-1)
+	panel := sort.SearchInts(starts, m.selected+1) - 1
+	switch dir {
+	case "up":
+		m.selected = (m.selected + n - 1) % n
+	case "down":
+		m.selected = (m.selected + 1) % n
+	case "left":
+		if m.selected == starts[panel] && panel > 0 {
+			panel--
+		}
+		m.selected = starts[panel]
+	case "right":
+		if panel+1 < len(starts) {
+			m.selected = starts[panel+1]
+		}
+	}
+	m.scrollToSelection()
+}
+
+// updateSearch handles a key while typing a / search. Each edit selects
+// the first row whose chip/label key contains the query, ignoring case;
+// enter keeps that selection and esc drops it.
+func (m *Model) updateSearch(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		return
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.searching, m.selecting = false, false
+		return
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.query)
+			m.query = m.query[:len(m.query)-size]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	default:
+		return
+	}
+
+	m.selecting = false
+	if m.query == "" {
+		return
+	}
+	q := strings.ToLower(m.query)
+	for i, r := range m.visibleRows() {
+		if strings.Contains(strings.ToLower(r.Key()), q) {
+			m.selecting, m.selected = true, i
+			m.scrollToSelection()
+			return
+		}
+	}
+}
+
+// selectKey moves the selection to a sensor's current row, e.g. after
+// pinning moved it.
+func (m *Model) selectKey(key string) {
+	for i, r := range m.visibleRows() {
+		if r.Key() == key {
+			m.selecting, m.selected = true, i
+			m.scrollToSelection()
+			return
+		}
+	}
+}
+
+// selectionMarker starts the selected row's label, in addition to the
+// reverse video, so the row is marked without color and can be found in
+// the rendered screen.
+const selectionMarker = "\u25b8"
+
+// cohortMarker ends the label of a sensor running hot against the rest of
+// its panel, e.g. one core well above its siblings.
+const cohortMarker = "\u25b4"
+
+// scrollToSelection scrolls just enough to bring the selected row on
+// screen.
+func (m *Model) scrollToSelection() {
+	if m.width == 0 {
+		return
+	}
+	for i, line := range m.contentLines() {
+		if !strings.Contains(line, selectionMarker) {
+			continue
+		}
+		if i < m.scroll {
+			m.scroll = i
+		} else if i >= m.scroll+m.visibleLines() {
+			m.scroll = i - m.visibleLines() + 1
+		}
+		return
+	}
+}
+
+// sensorGroup is the readings shown in one panel.
+type sensorGroup struct {
+	chip     string
+	adapter  string
+	readings []sensor.Reading
+	pinned   bool // the sticky panel of pinned sensors
+}
+
+// pinnedPanel is the panel key of the pinned sensors; chip names and
+// components never start with a NUL.
+const pinnedPanel = "\x00pinned"
+
+// panelGroups splits the readings that are not hidden into panels, in
+// screen order: pinned sensors first in pin order, then one panel per
+// chip, or per component when grouped, so e.g. both NVMe drives share one
+// panel and each row names its chip instead of the header. It returns the
+// panel keys in order.
+func (m Model) panelGroups() ([]string, map[string]*sensorGroup) {
+	groups := make(map[string]*sensorGroup)
+	var order []string
+
+	byKey := make(map[string]sensor.Reading, len(m.readings))
+	for _, r := range m.readings {
+		byKey[r.Key()] = r
+	}
+	pinned := make(map[string]bool, len(m.pinned))
+	for _, key := range m.pinned {
+		r, ok := byKey[key]
+		if !ok || m.hidden[key] {
+			continue
+		}
+		if len(order) == 0 {
+			groups[pinnedPanel] = &sensorGroup{pinned: true}
+			order = append(order, pinnedPanel)
+		}
+		groups[pinnedPanel].readings = append(groups[pinnedPanel].readings, r)
+		pinned[key] = true
+	}
+
+	for _, r := range m.readings {
+		if m.hidden[r.Key()] || pinned[r.Key()] {
+			continue
+		}
+		key := r.Chip
+		if m.grouped {
+			key = sensor.FriendlyName(r.Chip)
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &sensorGroup{chip: r.Chip, adapter: r.Adapter}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.readings = append(g.readings, r)
+	}
+	return order, groups
+}
+
+// visibleRows returns the rendered readings in screen order, the list
+// the selection indexes into.
+func (m Model) visibleRows() []sensor.Reading {
+	order, groups := m.panelGroups()
+	var rows []sensor.Reading
+	for _, key := range order {
+		rows = append(rows, groups[key].readings...)
+	}
+	return rows
+}
+
+// selectedReading returns the selected row, if any.
+func (m Model) selectedReading() (sensor.Reading, bool) {
+	rows := m.visibleRows()
+	if !m.selecting || m.selected < 0 || m.selected >= len(rows) {
+		return sensor.Reading{}, false
+	}
+	return rows[m.selected], true
+}
+
+// updateHolds folds the current chart columns into the peak-hold
+// envelope. Columns are counted from the right edge so the envelope stays
+// aligned with the scrolling sparkline at any chart width.
+func (m Model) updateHolds() {
+	if !m.peakHold {
+		return
+	}
+	for _, key := range m.order {
+		hist := m.history.Get(key)
+		if hist == nil {
+			continue
+		}
+		vals := hist.LastN(maxChartWidth)
+		hold := m.holds[key]
+		for j := 0; j < len(vals); j++ {
+			v := vals[len(vals)-1-j]
+			if j >= len(hold) {
+				hold = append(hold, v)
+			} else if v > hold[j] {
+				hold[j] = v
+			}
+		}
+		m.holds[key] = hold
+	}
+}
+
+// envelope returns a sensor's peak-hold values for a chart of the given
+// width, oldest column first.
+func (m Model) envelope(key string, width int) []float64 {
+	hold := m.holds[key]
+	if len(hold) > width {
+		hold = hold[:width]
+	}
+	out := make([]float64, len(hold))
+	for j, v := range hold {
+		out[len(hold)-1-j] = v
+	}
+	return out
+}
+
+// exportSVG writes the current history of every sensor to a timestamped
+// SVG file in the working directory and returns its path.
+func (m Model) exportSVG(now time.Time) (string, error) {
+	latest := make(map[string]sensor.Reading, len(m.readings))
+	for _, r := range m.readings {
+		latest[r.Key()] = r
+	}
+
+	var series []chart.Series
+	for _, key := range m.order {
+		hist := m.history.Get(key)
+		if hist == nil {
+			continue
+		}
+		r := latest[key]
+		series = append(series, chart.Series{
+			Name:      sensor.FriendlyName(r.Chip) + "  " + key,
+			Points:    hist.LastNPoints(len(hist.Points)),
+			High:      r.High,
+			Crit:      r.Crit,
+			HasHigh:   r.HasHigh,
+			HasCrit:   r.HasCrit,
+			Unit:      r.Unit,
+			Component: sensor.FriendlyName(r.Chip),
+		})
+	}
+
+	path := "sensors-" + now.Format("20060102-150405") + ".svg"
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := chart.WriteSVG(f, series, 1200); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// Err returns the error last shown in the title bar, including one from
+// saving the all-time peaks on quit, which happens too late to be shown.
+func (m Model) Err() error {
+	return m.err
+}
+
+// Summary describes the session for printing after the monitor exits:
+// its duration, each sensor's peak and every crit crossing, so an
+// unattended soak test leaves a takeaway without opening the viewer.
+func (m Model) Summary(end time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session %s (%s - %s)\n", fmtDuration(end.Sub(m.startTime)),
+		m.startTime.Format("15:04:05"), end.Format("15:04:05"))
+
+	keyW := 0
+	for _, key := range m.order {
+		if _, ok := m.session[key]; ok {
+			keyW = max(keyW, len(key))
+		}
+	}
+	if keyW > 0 {
+		b.WriteString("\nPeaks\n")
+		for _, key := range m.order {
+			if p, ok := m.session[key]; ok {
+				fmt.Fprintf(&b, "  %-*s  %s°C at %s\n", keyW, key, chart.Num("%5.1f", p.Temp), p.Time.Format("15:04:05"))
+			}
+		}
+	}
+
+	if len(m.breaches) == 0 {
+		b.WriteString("\nNo crit breaches\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nCrit breaches (%d)\n", len(m.breaches))
+	for _, e := range m.breaches {
+		fmt.Fprintf(&b, "  %s  %s  %s°C (crit %s°C)\n", e.Time.Format("15:04:05"), e.Key, chart.Num("%.1f", e.Temp), chart.Num("%.1f", e.Threshold))
+	}
+	return b.String()
+}
+
 func buildOrder(readings []sensor.Reading, existing []string) []string {
 	seen := make(map[string]bool)
 	for _, k := range existing {
@@ -173,7 +876,6 @@ var (
 	colorFooterBg = lipgloss.Color("235")
 	colorOk       = lipgloss.Color("78")
 	colorWarn     = lipgloss.Color("220")
-	colorHigh     = lipgloss.Color("208")
 	colorCrit     = lipgloss.Color("196")
 	colorPaused   = lipgloss.Color("196")
 )
@@ -185,9 +887,35 @@ func (m Model) View() string {
 		return "  Initializing..."
 	}
 
+	lines := m.contentLines()
+	visibleLines := m.visibleLines()
+	maxScroll := len(lines) - visibleLines
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.scroll > maxScroll {
+		m.scroll = maxScroll
+	}
+
+	start := m.scroll
+	end := start + visibleLines
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return strings.Join(lines[start:end], "\n")
+}
+
+// visibleLines is how many content lines fit on screen.
+func (m Model) visibleLines() int {
+	return max(m.height, 5)
+}
+
+// contentLines renders the whole screen before scrolling.
+func (m Model) contentLines() []string {
 	contentWidth := m.width - 2
-	if contentWidth < 40 {
-		contentWidth = 40
+	if contentWidth < 30 {
+		contentWidth = 30
 	}
 
 	var sections []string
@@ -204,43 +932,61 @@ func (m Model) View() string {
 		sections = append(sections, errBox)
 	}
 
-	if len(m.readings) == 0 {
+	if len(m.readings) == 0 || len(m.visibleRows()) == 0 {
+		text := "Waiting for sensor data..."
+		if len(m.readings) > 0 {
+			text = "All sensors are hidden, X shows them again"
+		}
 		waiting := lipgloss.NewStyle().
 			Foreground(colorDim).
 			Width(contentWidth).
 			Align(lipgloss.Center).
 			Padding(2, 0).
-			Render("Waiting for sensor data...")
+			Render(text)
 		sections = append(sections, waiting)
 	} else {
-		panels := m.renderSensorPanels(contentWidth)
-		sections = append(sections, panels...)
+		cols := panelColumns(contentWidth, minPanelWidth, maxColumns)
+		if m.compact {
+			cols = panelColumns(contentWidth, compactWidth, compactCols)
+		}
+		panels := m.renderSensorPanels((contentWidth+2)/cols - 2)
+		sections = append(sections, panelGrid(panels, cols)...)
 	}
 
 	sections = append(sections, m.renderFooter(contentWidth))
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return strings.Split(content, "\n")
+}
 
-	lines := strings.Split(content, "\n")
-	visibleLines := m.height
-	if visibleLines < 5 {
-		visibleLines = 5
-	}
-	maxScroll := len(lines) - visibleLines
-	if maxScroll < 0 {
-		maxScroll = 0
+// panelColumns returns how many chip panels of at least minWidth fit side
+// by side, up to most, so wide terminals show a grid instead of one long
+// column.
+func panelColumns(width, minWidth, most int) int {
+	cols := (width + 2) / (minWidth + 2)
+	if cols < 1 {
+		cols = 1
 	}
-	if m.scroll > maxScroll {
-		m.scroll = maxScroll
+	if cols > most {
+		cols = most
 	}
+	return cols
+}
 
-	start := m.scroll
-	end := start + visibleLines
-	if end > len(lines) {
-		end = len(lines)
+// panelGrid joins panels into rows of cols panels each, top-aligned.
+func panelGrid(panels []string, cols int) []string {
+	if cols <= 1 {
+		return panels
 	}
-
-	return strings.Join(lines[start:end], "\n")
+	var rows []string
+	for i := 0; i < len(panels); i += cols {
+		end := i + cols
+		if end > len(panels) {
+			end = len(panels)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, panels[i:end]...))
+	}
+	return rows
 }
 
 func (m Model) renderTitleBar(width int) string {
@@ -253,7 +999,7 @@ func (m Model) renderTitleBar(width int) string {
 
 	uptime := lipgloss.NewStyle().
 		Foreground(colorDim).
-		Render(fmt.Sprintf("up %s", fmtDuration(time.Since(m.startTime))))
+		Render(fmt.Sprintf("up %s", fmtDuration(now().Sub(m.startTime))))
 	statusParts = append(statusParts, uptime)
 
 	if !m.lastPoll.IsZero() {
@@ -263,6 +1009,13 @@ func (m Model) renderTitleBar(width int) string {
 		statusParts = append(statusParts, ts)
 	}
 
+	for _, f := range m.failing {
+		w := lipgloss.NewStyle().
+			Foreground(colorWarn).
+			Render("⚠ " + chart.Truncate(f, 40))
+		statusParts = append(statusParts, w)
+	}
+
 	if m.paused {
 		p := lipgloss.NewStyle().
 			Foreground(colorPaused).
@@ -271,10 +1024,25 @@ func (m Model) renderTitleBar(width int) string {
 		statusParts = append(statusParts, p)
 	}
 
+	if len(m.hidden) > 0 {
+		h := lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(fmt.Sprintf("%d hidden", len(m.hidden)))
+		statusParts = append(statusParts, h)
+	}
+
+	if m.status != "" {
+		st := lipgloss.NewStyle().
+			Foreground(colorOk).
+			Render(m.status)
+		statusParts = append(statusParts, st)
+	}
+
+	recLabel := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Render("REC")
 	if m.store != nil {
-		rec := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Render("REC") +
+		rec := recLabel +
 			lipgloss.NewStyle().
 				Foreground(colorDim).
 				Render(" "+store.DataDir())
@@ -284,6 +1052,18 @@ func (m Model) renderTitleBar(width int) string {
 	sep := lipgloss.NewStyle().Foreground(colorDim).Render(" \u2502 ")
 	right := strings.Join(statusParts, sep)
 
+	// On narrow terminals shorten the data dir to just REC, then drop
+	// status parts from the left until the bar fits on one line
+	fits := func() bool { return lipgloss.Width(logo)+lipgloss.Width(right)+5 <= width }
+	if !fits() && m.store != nil {
+		statusParts[len(statusParts)-1] = recLabel
+		right = strings.Join(statusParts, sep)
+	}
+	for !fits() && len(statusParts) > 1 {
+		statusParts = statusParts[1:]
+		right = strings.Join(statusParts, sep)
+	}
+
 	gap := width - lipgloss.Width(logo) - lipgloss.Width(right) - 4
 	if gap < 1 {
 		gap = 1
@@ -298,39 +1078,52 @@ func (m Model) renderTitleBar(width int) string {
 }
 
 func (m Model) renderSensorPanels(totalWidth int) []string {
-	type chipGroup struct {
-		chip     string
-		adapter  string
-		readings []sensor.Reading
+	chipOrder, chipMap := m.panelGroups()
+	visible := m.visibleRows()
+	selected, hasSelection := m.selectedReading()
+
+	innerWidth := totalWidth - 2 // panel padding
+	if innerWidth < 28 {
+		innerWidth = 28
 	}
-	chipMap := make(map[string]*chipGroup)
-	var chipOrder []string
 
-	for _, r := range m.readings {
-		g, ok := chipMap[r.Chip]
-		if !ok {
-			g = &chipGroup{chip: r.Chip, adapter: r.Adapter}
-			chipMap[r.Chip] = g
-			chipOrder = append(chipOrder, r.Chip)
+	// Grouped and pinned rows end with their chip ID, so the chart gives
+	// up room in every panel to keep the sparklines aligned
+	chipW := 0
+	hasPinned := len(chipOrder) > 0 && chipMap[chipOrder[0]].pinned
+	if m.grouped || hasPinned {
+		for _, r := range visible {
+			chipW = max(chipW, len(r.Chip))
 		}
-		g.readings = append(g.readings, r)
+		chipW = min(chipW, maxChipWidth) + 2
 	}
-
-	innerWidth := totalWidth - 4
-	if innerWidth < 30 {
-		innerWidth = 30
+	// A wider value column, e.g. for a 4-digit fan speed, also comes out
+	// of the chart so every row stays aligned
+	tempW := valueWidth(visible)
+	rowWidth := innerWidth - chipW - (tempW - minValueWidth)
+
+	// Responsive layout: drop the stats columns when the sparkline would
+	// get too short, and the sparkline itself (keeping name, temp and
+	// headroom) when even that does not fit. Compact mode always uses the
+	// narrowest layout.
+	showStats, showSpark := true, true
+	chartWidth := rowWidth - fullRowWidth
+	if chartWidth < minChartWidth {
+		showStats = false
+		chartWidth = rowWidth - sparkRowWidth
 	}
-
-	chartWidth := innerWidth - 60
-	if chartWidth < 15 {
-		chartWidth = 15
+	if chartWidth < minChartWidth || m.compact {
+		showSpark = false
 	}
-	if chartWidth > 140 {
-		chartWidth = 140
+	if chartWidth > maxChartWidth {
+		chartWidth = maxChartWidth
 	}
 
 	labelW := 14
-	tempW := 7
+	rateW := 6
+	if !showSpark {
+		rateW = 0
+	}
 
 	dimS := lipgloss.NewStyle().Foreground(colorDim)
 	valS := lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
@@ -338,13 +1131,30 @@ func (m Model) renderSensorPanels(totalWidth int) []string {
 	frameR := lipgloss.NewStyle().Foreground(colorBorder).Render("\u258F")
 
 	var panels []string
+	m.panels.begin()
 
 	for _, chipName := range chipOrder {
 		g := chipMap[chipName]
 
+		fp := m.panelFingerprint(g.readings, totalWidth, chipW, tempW)
+		if hasSelection && slices.ContainsFunc(g.readings, func(r sensor.Reading) bool { return r.Key() == selected.Key() }) {
+			fp += "|selected " + selected.Key()
+		}
+		if cached, ok := m.panels.get(chipName, fp); ok {
+			panels = append(panels, cached)
+			continue
+		}
+
 		var rows []string
+		rowChipW := chipW
+		if !m.grouped && !g.pinned {
+			rowChipW = 0
+		}
 
 		friendly := sensor.FriendlyName(g.chip)
+		if g.pinned {
+			friendly = "Pinned"
+		}
 		friendlyText := lipgloss.NewStyle().
 			Bold(true).
 			Foreground(colorChipName).
@@ -355,9 +1165,24 @@ func (m Model) renderSensorPanels(totalWidth int) []string {
 		adapterText := lipgloss.NewStyle().
 			Foreground(colorAdapter).
 			Render(g.adapter)
-		rows = append(rows, friendlyText+"  "+chipID+"  "+adapterText)
+		header := friendlyText + "  " + chipID + "  " + adapterText
+		if !showSpark || m.grouped || g.pinned {
+			header = friendlyText
+		}
+		if warning, ok := alert.FanFailure(m.chipThermals(g.readings)); ok && !g.pinned {
+			header += "  " + lipgloss.NewStyle().
+				Foreground(colorCrit).
+				Bold(true).
+				Render("\u26a0 "+warning)
+		}
+		rows = append(rows, header)
 
 		var lastPts []history.Point
+		// The pinned panel mixes chips, so it is no cohort
+		var outliers map[string]bool
+		if !g.pinned {
+			outliers = alert.CohortOutliers(g.readings, m.sigma)
+		}
 
 		for _, r := range g.readings {
 			hist := m.history.Get(r.Key())
@@ -365,33 +1190,56 @@ func (m Model) renderSensorPanels(totalWidth int) []string {
 				continue
 			}
 
-			rangeMin := math.Max(0, hist.Min-5)
-			rangeMax := hist.Peak + 5
-			if r.HasCrit && r.Crit > rangeMax {
-				rangeMax = r.Crit + 5
+			rangeMin, rangeMax := chart.SensorRange(sensor.FriendlyName(r.Chip), r.Unit, hist.Min, hist.Peak, r.High, r.Crit, r.HasHigh, r.HasCrit)
+
+			labelS := lipgloss.NewStyle().Foreground(colorLabel).Width(labelW)
+			labelText := chart.Truncate(r.Label, labelW)
+			if hasSelection && r.Key() == selected.Key() {
+				labelS = labelS.Reverse(true)
+				labelText = selectionMarker + chart.Truncate(r.Label, labelW-1)
 			}
-			if r.HasHigh && r.High > rangeMax {
-				rangeMax = r.High + 5
+			if outliers[r.Key()] {
+				labelS = labelS.Foreground(colorWarn).Bold(true)
+				labelText = chart.Truncate(labelText, labelW-2)
+				labelText += strings.Repeat(" ", labelW-1-lipgloss.Width(labelText)) + cohortMarker
 			}
-
-			label := lipgloss.NewStyle().
-				Foreground(colorLabel).
-				Width(labelW).
-				Render(truncate(r.Label, labelW))
+			label := labelS.Render(labelText)
 
 			temp := lipgloss.NewStyle().
 				Width(tempW).
 				Align(lipgloss.Right).
-				Render(chart.RenderTempValue(r.Temp, r.High, r.Crit, r.HasHigh, r.HasCrit))
+				Render(renderValue(r))
 
+			if !showSpark {
+				rows = append(rows, withChip(label+" "+temp+renderHeadroom(r), r.Chip, rowChipW, innerWidth))
+				continue
+			}
+
+			// A lone point would chart as one block with equal stats;
+			// say so until there is a line to draw
 			pts := hist.LastNPoints(chartWidth)
-			lastPts = pts
-			spark := chart.RenderSparklinePoints(pts, chartWidth, rangeMin, rangeMax, r.High, r.Crit, r.HasHigh, r.HasCrit)
+			collecting := chart.Collecting(pts)
+			spark := chart.RenderCollecting(chartWidth)
+			if !collecting {
+				lastPts = pts
+				spark = chart.RenderSparklinePoints(pts, chartWidth, rangeMin, rangeMax, r.High, r.Crit, r.HasHigh, r.HasCrit)
+			}
 			framedSpark := frameL + spark + frameR
 
-			stats := dimS.Render(" avg") + valS.Render(fmt.Sprintf("%5.1f", hist.Avg())) +
-				dimS.Render(" lo") + valS.Render(fmt.Sprintf("%5.1f", hist.Min)) +
-				dimS.Render(" pk") + valS.Render(fmt.Sprintf("%5.1f", hist.Peak))
+			var stats string
+			if showStats {
+				stats = dimS.Render(" avg") + valS.Render(fmtStat(hist.Avg(), r.Unit)) +
+					dimS.Render(" lo") + valS.Render(fmtStat(hist.Min, r.Unit)) +
+					dimS.Render(" pk") + valS.Render(fmtStat(hist.Peak, r.Unit))
+				if p, ok := m.peaks[r.Key()]; ok {
+					stats += dimS.Render(" all") + valS.Render(chart.Num("%5.1f", p.Temp))
+				} else {
+					stats += strings.Repeat(" ", allPeakWidth)
+				}
+				if collecting {
+					stats = strings.Repeat(" ", lipgloss.Width(stats))
+				}
+			}
 
 			var threshTags string
 			if r.HasHigh {
@@ -401,14 +1249,26 @@ func (m Model) renderSensorPanels(totalWidth int) []string {
 				threshTags += dimS.Render(" C") + lipgloss.NewStyle().Foreground(colorCrit).Render(fmt.Sprintf("%.0f", r.Crit))
 			}
 
-			row := label + " " + temp + " " + framedSpark + stats + threshTags
-			rows = append(rows, row)
+			rate := strings.Repeat(" ", rateW)
+			if r.Unit == "" {
+				rate = renderRate(hist.Rate())
+			}
+
+			if m.peakHold {
+				pad := strings.Repeat(" ", labelW+tempW+rateW+3)
+				rows = append(rows, pad+chart.RenderEnvelope(m.envelope(r.Key(), chartWidth), chartWidth, rangeMin, rangeMax))
+			}
+			row := label + " " + temp + rate + " " + framedSpark + stats + threshTags
+			rows = append(rows, withChip(row, r.Chip, rowChipW, innerWidth))
+			if m.detail && hasSelection && r.Key() == selected.Key() {
+				rows = append(rows, renderDetail(hist.LastNPoints(chartWidth), r, chartWidth, labelW+tempW+rateW+2, rangeMin, rangeMax)...)
+			}
 		}
 
 		if lastPts != nil {
 			timeline := chart.RenderTimeline(lastPts, chartWidth)
 			if strings.TrimSpace(timeline) != "" {
-				pad := strings.Repeat(" ", labelW+tempW+2)
+				pad := strings.Repeat(" ", labelW+tempW+rateW+2)
 				rows = append(rows, pad+" "+timeline)
 			}
 		}
@@ -421,17 +1281,108 @@ func (m Model) renderSensorPanels(totalWidth int) []string {
 			Width(totalWidth).
 			Render(panelContent)
 
+		m.panels.put(chipName, fp, panel)
 		panels = append(panels, panel)
 	}
 
 	return panels
 }
 
+// renderValue renders a reading's current value: a colored temperature,
+// or a neutral power or fan figure with its unit.
+func renderValue(r sensor.Reading) string {
+	if r.Unit != "" {
+		return chart.RenderUnitValue(r.Temp, r.Unit)
+	}
+	return chart.RenderTempValue(r.Temp, r.High, r.Crit, r.HasHigh, r.HasCrit)
+}
+
+// fmtStat formats an avg/lo/pk figure in 5 columns, dropping the decimal
+// for fan speeds and percentages as RenderUnitValue does, so a 4-digit
+// RPM stays in its column.
+func fmtStat(v float64, unit string) string {
+	if unit != "" && unit != "W" {
+		return fmt.Sprintf("%5.0f", v)
+	}
+	return chart.Num("%5.1f", v)
+}
+
+// valueWidth returns the value column width for the readings on screen:
+// minValueWidth, or the widest value if one does not fit, so that no row
+// wraps and every sparkline starts in the same column.
+func valueWidth(readings []sensor.Reading) int {
+	w := minValueWidth
+	for _, r := range readings {
+		w = max(w, lipgloss.Width(renderValue(r)))
+	}
+	return w
+}
+
+// panelFingerprint captures everything a panel's rendering depends on:
+// layout, view toggles, the readings, and the state of each sensor's
+// history (a new point moves the last timestamp; a reset moves min/peak).
+func (m Model) panelFingerprint(readings []sensor.Reading, width, chipW, tempW int) string {
+	var b []byte
+	b = strconv.AppendInt(b, int64(width), 10)
+	b = strconv.AppendInt(b, int64(chipW), 10)
+	b = strconv.AppendInt(b, int64(tempW), 10)
+	b = strconv.AppendBool(b, m.grouped)
+	b = strconv.AppendBool(b, m.peakHold)
+	b = strconv.AppendBool(b, m.compact)
+	for _, r := range readings {
+		b = fmt.Appendf(b, "|%s|%s|%g|%g|%g|%t|%t|%s", r.Key(), r.Adapter, r.Temp, r.High, r.Crit, r.HasHigh, r.HasCrit, r.Unit)
+		if hist := m.history.Get(r.Key()); hist != nil && len(hist.Points) > 0 {
+			last := hist.Points[len(hist.Points)-1]
+			b = fmt.Appendf(b, "|%d|%d|%g|%g", len(hist.Points), last.Time.UnixNano(), hist.Min, hist.Peak)
+		}
+		if p, ok := m.peaks[r.Key()]; ok {
+			b = strconv.AppendFloat(b, p.Temp, 'g', -1, 64)
+		}
+	}
+	return string(b)
+}
+
+// renderDetail renders the T chart for one sensor, lined up under its
+// sparkline, with the range's top and bottom values in the margin.
+func renderDetail(pts []history.Point, r sensor.Reading, width, margin int, rangeMin, rangeMax float64) []string {
+	frameS := lipgloss.NewStyle().Foreground(colorBorder)
+	dimS := lipgloss.NewStyle().Foreground(colorDim)
+	tall := chart.RenderTall(pts, width, detailHeight, rangeMin, rangeMax, r.High, r.Crit, r.HasHigh, r.HasCrit)
+	rows := make([]string, len(tall))
+	for i, line := range tall {
+		axis := ""
+		switch i {
+		case 0:
+			axis = chart.Num("%.0f", rangeMax)
+		case len(tall) - 1:
+			axis = chart.Num("%.0f", rangeMin)
+		}
+		rows[i] = dimS.Render(fmt.Sprintf("%*s", margin-1, axis)) + " " +
+			frameS.Render("\u2595") + line + frameS.Render("\u258F")
+	}
+	return rows
+}
+
+// withChip adds the dim chip ID as a last column of a sensor row in grouped
+// mode (chipW > 0), truncated to what is left of width.
+func withChip(row, chip string, chipW, width int) string {
+	if chipW == 0 {
+		return row
+	}
+	room := min(chipW, width-lipgloss.Width(row)) - 2
+	if room < 4 {
+		return row
+	}
+	gap := width - lipgloss.Width(row) - room
+	return row + strings.Repeat(" ", gap) + lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(chart.Truncate(chip, room))
+}
+
 func (m Model) renderFooter(width int) string {
-	okS := lipgloss.NewStyle().Foreground(colorOk).Render("\u2588\u2588")
-	warnS := lipgloss.NewStyle().Foreground(colorWarn).Render("\u2588\u2588")
-	highS := lipgloss.NewStyle().Foreground(colorHigh).Render("\u2588\u2588")
-	critS := lipgloss.NewStyle().Foreground(colorCrit).Render("\u2588\u2588")
+	th := chart.ActiveTheme()
+	okS := lipgloss.NewStyle().Foreground(th.Ok).Render("\u2588\u2588")
+	warnS := lipgloss.NewStyle().Foreground(th.Warm).Render("\u2588\u2588")
+	highS := lipgloss.NewStyle().Foreground(th.High).Render("\u2588\u2588")
+	critS := lipgloss.NewStyle().Foreground(th.Crit).Render("\u2588\u2588")
 	tickS := lipgloss.NewStyle().Foreground(lipgloss.Color("239")).Render("\u2502")
 
 	dimS := lipgloss.NewStyle().Foreground(colorDim)
@@ -443,8 +1394,17 @@ func (m Model) renderFooter(width int) string {
 
 	keys := dimS.Render("q") + lipgloss.NewStyle().Foreground(colorLabel).Render(":quit") +
 		dimS.Render("  j/k") + lipgloss.NewStyle().Foreground(colorLabel).Render(":scroll") +
-		dimS.Render("  p") + lipgloss.NewStyle().Foreground(colorLabel).Render(":pause")
+		dimS.Render("  p") + lipgloss.NewStyle().Foreground(colorLabel).Render(":pause") +
+		dimS.Render("  P") + lipgloss.NewStyle().Foreground(colorLabel).Render(":svg")
+
+	if m.searching {
+		keys = dimS.Render("/") + lipgloss.NewStyle().Foreground(colorLabel).Render(m.query+"\u2588") +
+			dimS.Render("  enter:keep  esc:cancel")
+	}
 
+	if lipgloss.Width(legend)+lipgloss.Width(keys)+5 > width {
+		legend = ""
+	}
 	gap := width - lipgloss.Width(legend) - lipgloss.Width(keys) - 4
 	if gap < 1 {
 		gap = 1
@@ -458,14 +1418,77 @@ func (m Model) renderFooter(width int) string {
 		Render(legend + filler + keys)
 }
 
-func truncate(s string, w int) string {
-	if len(s) <= w {
-		return s
+// hottest returns the hottest temperature reading.
+func hottest(readings []sensor.Reading) (sensor.Reading, bool) {
+	var best sensor.Reading
+	found := false
+	for _, r := range readings {
+		if r.Unit == "" && (!found || r.Temp > best.Temp) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+// chipThermals gathers one chip's temperature trend and fan state from
+// history for the fan-failure heuristic.
+func (m Model) chipThermals(readings []sensor.Reading) alert.ChipThermals {
+	var c alert.ChipThermals
+	for _, r := range readings {
+		hist := m.history.Get(r.Key())
+		if hist == nil {
+			continue
+		}
+		switch r.Unit {
+		case "":
+			c.TempRate = math.Max(c.TempRate, hist.Rate())
+			c.Rise = math.Max(c.Rise, alert.Rise(hist.LastNPoints(len(hist.Points)), alert.SustainedWindow))
+		case "RPM", "%":
+			c.HasFan = true
+			c.FanSpeed = r.Temp
+			c.FanRate = hist.Rate()
+		}
+	}
+	return c
+}
+
+// renderHeadroom formats how far a reading is below its nearest upcoming
+// threshold, for the narrow layout that has no room for a sparkline.
+func renderHeadroom(r sensor.Reading) string {
+	dimS := lipgloss.NewStyle().Foreground(colorDim)
+	switch {
+	case r.Unit != "":
+		return ""
+	case r.HasHigh && r.Temp < r.High:
+		return dimS.Render(fmt.Sprintf(" %3.0f\u00b0 to high", r.High-r.Temp))
+	case r.HasCrit && r.Temp < r.Crit:
+		return lipgloss.NewStyle().Foreground(colorWarn).Render(fmt.Sprintf(" %3.0f\u00b0 to crit", r.Crit-r.Temp))
+	case r.HasCrit:
+		return lipgloss.NewStyle().Foreground(colorCrit).Bold(true).Render(" over crit")
+	}
+	return ""
+}
+
+// renderRate formats a °C/s rate of change as a 6-column arrow indicator,
+// dim when steady and highlighted as it approaches spikeRate.
+func renderRate(rate float64) string {
+	arrow := "\u2192"
+	switch {
+	case rate >= 0.05:
+		arrow = "\u2191"
+	case rate <= -0.05:
+		arrow = "\u2193"
 	}
-	if w <= 3 {
-		return s[:w]
+	text := " " + arrow + chart.Num("%4.1f", math.Abs(rate))
+
+	style := lipgloss.NewStyle().Foreground(colorDim)
+	switch {
+	case rate >= spikeRate:
+		style = lipgloss.NewStyle().Foreground(colorCrit).Bold(true)
+	case rate >= warmRate:
+		style = lipgloss.NewStyle().Foreground(colorWarn)
 	}
-	return s[:w-1] + "\u2026"
+	return style.Render(text)
 }
 
 func fmtDuration(d time.Duration) string {
@@ -480,3 +1503,61 @@ func fmtDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%dm%02ds", m, s)
 }
+
+// ── Panel cache ──────────────────────────────────────────────────────
+
+// panelCache keeps the last rendered string of each panel with the
+// fingerprint of its inputs. View runs after every message (ticks, keys,
+// resizes), and while paused or scrolling most panels have not changed,
+// so they are reused instead of re-rendered.
+//
+// Each render moves the entries it uses from prev to cur, so panels that
+// disappear (unplugged devices, a regrouping) are dropped a render later.
+// A nil *panelCache disables caching.
+type panelCache struct {
+	prev, cur map[string]panelEntry
+}
+
+type panelEntry struct {
+	fingerprint string
+	panel       string
+}
+
+func newPanelCache() *panelCache {
+	return &panelCache{cur: make(map[string]panelEntry)}
+}
+
+// begin starts a render pass.
+func (c *panelCache) begin() {
+	if c == nil {
+		return
+	}
+	c.prev, c.cur = c.cur, make(map[string]panelEntry, len(c.cur))
+}
+
+func (c *panelCache) get(key, fingerprint string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	e, ok := c.prev[key]
+	if !ok || e.fingerprint != fingerprint {
+		return "", false
+	}
+	c.cur[key] = e
+	return e.panel, true
+}
+
+func (c *panelCache) put(key, fingerprint, panel string) {
+	if c == nil {
+		return
+	}
+	c.cur[key] = panelEntry{fingerprint, panel}
+}
+
+// reset drops every entry, for state the fingerprint does not capture.
+func (c *panelCache) reset() {
+	if c == nil {
+		return
+	}
+	c.prev, c.cur = nil, make(map[string]panelEntry)
+}
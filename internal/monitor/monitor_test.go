@@ -0,0 +1,678 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/luki/sensors/internal/alert"
+	"github.com/luki/sensors/internal/history"
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+	"github.com/luki/sensors/internal/testutil"
+)
+
+// testModel returns a monitor with a few minutes of history for a CPU and
+// an NVMe drive, without touching the disk store.
+func testModel(width int) Model {
+	m := Model{
+		history:   history.NewStore(historySize),
+		width:     width,
+		height:    100,
+		startTime: time.Now(),
+	}
+	m.readings = []sensor.Reading{
+		{Chip: "coretemp-isa-0000", Adapter: "ISA adapter", Label: "Package id 0", Temp: 62, High: 80, Crit: 100, HasHigh: true, HasCrit: true},
+		{Chip: "coretemp-isa-0000", Adapter: "ISA adapter", Label: "Core 0", Temp: 58, High: 80, Crit: 100, HasHigh: true, HasCrit: true},
+		{Chip: "nvme-pci-0300", Adapter: "PCI adapter", Label: "Composite", Temp: 44, High: 81.8, Crit: 84.8, HasHigh: true, HasCrit: true},
+	}
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	for i := 0; i < 180; i++ {
+		for _, r := range m.readings {
+			m.history.Record(r.Key(), r.Temp+float64(i%7), base.Add(time.Duration(i)*time.Second))
+		}
+	}
+	return m
+}
+
+func TestViewWidths(t *testing.T) {
+	tests := []struct {
+		width    int
+		spark    bool
+		stats    bool
+		headroom bool
+	}{
+		{40, false, false, true},
+		{60, true, false, false},
+		{100, true, true, false},
+		{200, true, true, false},
+	}
+	for _, tt := range tests {
+		view := testModel(tt.width).View()
+		for i, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w > tt.width {
+				t.Errorf("width %d: line %d is %d columns: %q", tt.width, i, w, line)
+			}
+		}
+		if got := strings.Contains(view, "▕"); got != tt.spark {
+			t.Errorf("width %d: sparkline shown = %v, want %v", tt.width, got, tt.spark)
+		}
+		if got := strings.Contains(view, " avg"); got != tt.stats {
+			t.Errorf("width %d: stats shown = %v, want %v", tt.width, got, tt.stats)
+		}
+		if got := strings.Contains(view, "to high"); got != tt.headroom {
+			t.Errorf("width %d: headroom shown = %v, want %v", tt.width, got, tt.headroom)
+		}
+	}
+}
+
+func TestViewColumns(t *testing.T) {
+	tests := []struct {
+		width int
+		cols  int
+	}{
+		{100, 1},
+		{200, 1},
+		{240, 2},
+		{400, 3},
+	}
+	for _, tt := range tests {
+		view := testModel(tt.width).View()
+		maxTops := 0
+		for i, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w > tt.width {
+				t.Errorf("width %d: line %d is %d columns", tt.width, i, w)
+			}
+			if n := strings.Count(line, "╭"); n > maxTops {
+				maxTops = n
+			}
+		}
+		// testModel has two chips, so at most two panels share a row
+		want := tt.cols
+		if want > 2 {
+			want = 2
+		}
+		if maxTops != want {
+			t.Errorf("width %d: %d panels side by side, want %d", tt.width, maxTops, want)
+		}
+	}
+}
+
+func TestCompactView(t *testing.T) {
+	m := testModel(200)
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = next.(Model)
+	view := m.View()
+	if strings.Contains(view, "▕") || strings.Contains(view, " avg") {
+		t.Error("compact view still shows sparklines or stats")
+	}
+	if !strings.Contains(view, "to high") {
+		t.Error("compact view should show the headroom")
+	}
+	maxTops := 0
+	for i, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 200 {
+			t.Errorf("line %d is %d columns", i, w)
+		}
+		maxTops = max(maxTops, strings.Count(line, "╭"))
+	}
+	// Narrow panels share a row where two full ones would not fit
+	if maxTops != 2 {
+		t.Errorf("%d panels side by side, want 2", maxTops)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !strings.Contains(next.(Model).View(), "▕") {
+		t.Error("second v did not bring the sparklines back")
+	}
+}
+
+func TestPeakHold(t *testing.T) {
+	m := testModel(120)
+	m.order = buildOrder(m.readings, nil)
+	key := m.readings[0].Key()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = next.(Model)
+	if !m.peakHold {
+		t.Fatal("H did not enable peak hold")
+	}
+	peak := m.envelope(key, 10)
+
+	// Temperatures drop; the envelope keeps the earlier peaks
+	base := time.Date(2026, 2, 21, 15, 0, 0, 0, time.Local)
+	for i := 0; i < 10; i++ {
+		m.history.Record(key, 30, base.Add(time.Duration(i)*time.Second))
+	}
+	m.updateHolds()
+	after := m.envelope(key, 10)
+	for i := range peak {
+		if after[i] != peak[i] {
+			t.Errorf("column %d: hold %f dropped to %f", i, peak[i], after[i])
+		}
+	}
+	plain := m
+	plain.peakHold = false
+	if got, base := strings.Count(m.View(), "\n"), strings.Count(plain.View(), "\n"); got != base+len(m.readings) {
+		t.Errorf("peak hold view has %d lines, want one envelope row per sensor over %d", got, base)
+	}
+
+	// r resets the envelope and the session min/peak
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(Model)
+	for _, v := range m.envelope(key, 10) {
+		if v != 30 {
+			t.Fatalf("after reset: envelope %v, want all 30", m.envelope(key, 10))
+		}
+	}
+	if hist := m.history.Get(key); hist.Peak != 30 || hist.Min != 30 {
+		t.Errorf("after reset: min %f peak %f, want 30", hist.Min, hist.Peak)
+	}
+}
+
+func TestGroupByComponent(t *testing.T) {
+	m := testModel(120)
+	second := sensor.Reading{Chip: "nvme-pci-0400", Adapter: "PCI adapter", Label: "Composite", Temp: 38, High: 81.8, Crit: 84.8, HasHigh: true, HasCrit: true}
+	m.readings = append(m.readings, second)
+	m.history.Record(second.Key(), second.Temp, time.Now())
+
+	if got := len(m.renderSensorPanels(120)); got != 3 {
+		t.Fatalf("by chip: %d panels, want 3", got)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = next.(Model)
+	panels := m.renderSensorPanels(120)
+	if len(panels) != 2 {
+		t.Fatalf("by component: %d panels, want 2", len(panels))
+	}
+	for _, chip := range []string{"nvme-pci-0300", "nvme-pci-0400"} {
+		if !strings.Contains(panels[1], chip) {
+			t.Errorf("grouped NVMe panel does not name %s", chip)
+		}
+	}
+	for i, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > 120 {
+			t.Errorf("line %d is %d columns", i, w)
+		}
+	}
+}
+
+func TestAllTimePeak(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	key := m.readings[2].Key()
+	m.peaks = store.Peaks{key: {Temp: 71, Time: time.Now().Add(-24 * time.Hour)}}
+
+	hot := append([]sensor.Reading(nil), m.readings...)
+	hot[2].Temp = 74
+	next, _ := m.Update(sensorDataMsg{readings: hot, time: time.Now()})
+	m = next.(Model)
+	if p := m.peaks[key]; p.Temp != 74 {
+		t.Errorf("all-time peak: got %f, want 74", p.Temp)
+	}
+	if p := m.peaks[hot[0].Key()]; p.Temp != 62 {
+		t.Errorf("new sensor peak: got %f, want 62", p.Temp)
+	}
+	if !strings.Contains(m.View(), " all 74.0") {
+		t.Error("all-time peak not shown")
+	}
+}
+
+func TestPanelCache(t *testing.T) {
+	m := testModel(120)
+	m.panels = newPanelCache()
+	first := m.renderSensorPanels(120)
+
+	// Mark the cached CPU panel so reuse is visible
+	key := m.readings[0].Chip
+	e := m.panels.cur[key]
+	e.panel = "cached"
+	m.panels.cur[key] = e
+
+	if got := m.renderSensorPanels(120); got[0] != "cached" || got[1] != first[1] {
+		t.Fatal("unchanged panels were re-rendered")
+	}
+	if got := m.renderSensorPanels(100); got[0] == "cached" {
+		t.Error("panel reused across a width change")
+	}
+
+	m.renderSensorPanels(120)
+	e = m.panels.cur[key]
+	e.panel = "cached"
+	m.panels.cur[key] = e
+	m.history.Record(m.readings[0].Key(), 70, time.Now())
+	got := m.renderSensorPanels(120)
+	if got[0] == "cached" {
+		t.Error("panel reused after a new reading")
+	}
+	if got[1] != first[1] {
+		t.Error("NVMe panel changed without new data")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	m.session = store.Peaks{}
+	m.startTime = time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+
+	hot := append([]sensor.Reading(nil), m.readings...)
+	hot[0].Temp = 101
+	at := m.startTime.Add(90 * time.Minute)
+	next, _ := m.Update(sensorDataMsg{readings: hot, time: at})
+	m = next.(Model)
+
+	out := m.Summary(m.startTime.Add(2 * time.Hour))
+	for _, want := range []string{
+		"Session 2h00m00s (14:00:00 - 16:00:00)",
+		"coretemp-isa-0000/Package id 0  101.0°C at 15:30:00",
+		"nvme-pci-0300/Composite          44.0°C at 15:30:00",
+		"Crit breaches (1)",
+		"15:30:00  coretemp-isa-0000/Package id 0  101.0°C (crit 100.0°C)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer func(clock func() time.Time) { now = clock }(now)
+
+	start := time.Date(2026, 2, 21, 13, 0, 0, 0, time.Local)
+	now = func() time.Time { return start.Add(63 * time.Minute) }
+
+	for _, width := range []int{40, 80, 120, 200} {
+		m := testModel(width)
+		m.height = 40
+		m.startTime = start
+		m.lastPoll = start.Add(63 * time.Minute)
+		testutil.CheckGolden(t, fmt.Sprintf("view-%d", width), m.View())
+	}
+}
+
+func TestViewGoldenHot(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer func(clock func() time.Time) { now = clock }(now)
+
+	start := time.Date(2026, 2, 21, 13, 0, 0, 0, time.Local)
+	now = func() time.Time { return start.Add(63 * time.Minute) }
+
+	// 3-digit temps and a 4-digit fan speed must not wrap or shift the
+	// sparklines out of line
+	m := testModel(120)
+	m.height = 40
+	m.startTime = start
+	m.readings[0].Temp = 104.5
+	m.readings = append(m.readings,
+		sensor.Reading{Chip: "amdgpu-pci-0600", Adapter: "PCI adapter", Label: "edge", Temp: 100, High: 100, Crit: 110, HasHigh: true, HasCrit: true},
+		sensor.Reading{Chip: "amdgpu-pci-0600", Adapter: "PCI adapter", Label: "fan1", Temp: 2150, Unit: "RPM"},
+		sensor.Reading{Chip: "amdgpu-pci-0600", Adapter: "PCI adapter", Label: "PPT", Temp: 231.5, Unit: "W"},
+	)
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	for i := 0; i < 180; i++ {
+		for _, r := range m.readings[3:] {
+			m.history.Record(r.Key(), r.Temp+float64(i%7), base.Add(time.Duration(i)*time.Second))
+		}
+	}
+
+	view := m.View()
+	testutil.CheckGolden(t, "view-hot", view)
+
+	var starts []int
+	for _, line := range strings.Split(view, "\n") {
+		if i := strings.Index(line, "▕"); i >= 0 {
+			starts = append(starts, lipgloss.Width(line[:i]))
+		}
+	}
+	for _, s := range starts {
+		if s != starts[0] {
+			t.Fatalf("sparklines start in different columns: %v", starts)
+		}
+	}
+}
+
+func TestExpireMissingSensor(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	m.expire = 3
+	all := m.readings
+	poll := func(rs []sensor.Reading) {
+		next, _ := m.Update(sensorDataMsg{readings: rs, time: time.Now()})
+		m = next.(Model)
+	}
+	const nvme = "nvme-pci-0300/Composite"
+
+	poll(all)
+	// The drive goes away; it survives a couple of missed polls
+	for i := 0; i < 2; i++ {
+		poll(all[:2])
+	}
+	if !slices.Contains(m.order, nvme) || m.history.Get(nvme) == nil {
+		t.Fatal("sensor expired before missing expire polls")
+	}
+	poll(all[:2])
+	if slices.Contains(m.order, nvme) || m.history.Get(nvme) != nil {
+		t.Errorf("sensor missing for 3 polls still tracked: order %q", m.order)
+	}
+	if len(m.order) != 2 || m.history.Get("coretemp-isa-0000/Core 0") == nil {
+		t.Errorf("present sensors were dropped: order %q", m.order)
+	}
+
+	// Coming back starts a fresh buffer
+	poll(all)
+	if h := m.history.Get(nvme); h == nil || len(h.Points) != 1 {
+		t.Error("returning sensor was not tracked again")
+	}
+}
+
+func TestExpireKeepsSessionPeak(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	m.session = store.Peaks{}
+	m.panels = newPanelCache()
+	m.expire = 3
+	all := m.readings
+	poll := func(rs []sensor.Reading) {
+		next, _ := m.Update(sensorDataMsg{readings: rs, time: time.Now()})
+		m = next.(Model)
+	}
+	const nvme = "nvme-pci-0300/Composite"
+
+	poll(all)
+	for i := 0; i < 3; i++ {
+		poll(all[:2])
+	}
+	if !slices.Contains(m.order, nvme) || m.history.Get(nvme) != nil {
+		t.Fatalf("expired sensor with a session peak: order %q, want kept without history", m.order)
+	}
+
+	// Later polls must not expire it again, which would reset the panel
+	// cache every poll
+	m.renderSensorPanels(120)
+	for i := 0; i < 5; i++ {
+		poll(all[:2])
+		if len(m.panels.cur) == 0 {
+			t.Fatalf("panel cache reset on poll %d after expiry", i+1)
+		}
+	}
+
+	poll(all)
+	if m.gone[nvme] {
+		t.Error("returning sensor still marked expired")
+	}
+}
+
+func TestDetailChart(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	lines := func() []string { return strings.Split(strings.Join(m.renderSensorPanels(120), "\n"), "\n") }
+	before := len(lines())
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = next.(Model)
+	if r, ok := m.selectedReading(); !ok || r.Label != "Package id 0" {
+		t.Fatalf("T should select the first sensor, got %+v", r)
+	}
+	got := lines()
+	if len(got) != before+detailHeight {
+		t.Fatalf("detail chart added %d lines, want %d", len(got)-before, detailHeight)
+	}
+	// Package id 0 charts 62-68 with H80 C100: both limit rules are drawn
+	rules := 0
+	for _, l := range got {
+		if strings.Contains(l, "▕──────") {
+			rules++
+		}
+	}
+	if rules != 2 {
+		t.Errorf("got %d limit lines, want 2:\n%s", rules, strings.Join(got, "\n"))
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = next.(Model)
+	if len(lines()) != before {
+		t.Error("second T did not remove the detail chart")
+	}
+}
+
+func TestHideSensor(t *testing.T) {
+	m := testModel(120)
+	var saved []string
+	m.saveHide = func(keys []string) error { saved = keys; return nil }
+	press := func(key tea.KeyMsg) {
+		next, _ := m.Update(key)
+		m = next.(Model)
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(runes("x"))
+	if len(m.visibleRows()) != 3 || saved != nil {
+		t.Fatal("x without a selection should not hide anything")
+	}
+
+	// Select the second row, Core 0, and hide it
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	if r, ok := m.selectedReading(); !ok || r.Label != "Core 0" {
+		t.Fatalf("selected %+v, want Core 0", r)
+	}
+	if !strings.Contains(m.View(), selectionMarker+"Core 0") {
+		t.Error("selected row is not marked")
+	}
+	press(runes("x"))
+	if strings.Contains(strings.Join(m.renderSensorPanels(120), "\n"), "Core 0") {
+		t.Error("hidden sensor still rendered")
+	}
+	if want := []string{"coretemp-isa-0000/Core 0"}; !slices.Equal(saved, want) {
+		t.Errorf("saved %q, want %q", saved, want)
+	}
+	// The selection moves on to the next row
+	if r, _ := m.selectedReading(); r.Label != "Composite" {
+		t.Errorf("selection after hide: %+v", r)
+	}
+
+	// Hidden sensors are still polled into history
+	m.detector = alert.NewDetector()
+	next, _ := m.Update(sensorDataMsg{readings: m.readings, time: time.Now()})
+	m = next.(Model)
+	if hist := m.history.Get("coretemp-isa-0000/Core 0"); hist == nil || len(hist.Points) != 181 {
+		t.Error("hidden sensor should still be recorded")
+	}
+
+	press(runes("X"))
+	if !strings.Contains(m.View(), "Core 0") || len(saved) != 0 {
+		t.Errorf("X should show every sensor again and save an empty list, saved %q", saved)
+	}
+}
+
+func TestSelectionCursor(t *testing.T) {
+	m := testModel(120)
+	m.height = 8
+	press := func(key string) {
+		t.Helper()
+		next, _ := m.Update(tea.KeyMsg{Type: map[string]tea.KeyType{
+			"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+		}[key]})
+		m = next.(Model)
+	}
+	selected := func() string {
+		r, _ := m.selectedReading()
+		return r.Label
+	}
+
+	if _, ok := m.selectedReading(); ok || strings.Contains(m.View(), selectionMarker) {
+		t.Fatal("nothing is selected until a key moves the cursor")
+	}
+	press("down")
+	if selected() != "Package id 0" {
+		t.Fatalf("first move selects the first row, got %q", selected())
+	}
+	press("right")
+	if selected() != "Composite" || m.selected != 2 {
+		t.Fatalf("right jumps to the next panel, got %q at %d", selected(), m.selected)
+	}
+	// The NVMe panel is below the fold at this height, so the view follows
+	if !strings.Contains(m.View(), selectionMarker+"Composite") {
+		t.Errorf("selected row scrolled out of view (scroll %d):\n%s", m.scroll, m.View())
+	}
+	press("left")
+	press("left")
+	if selected() != "Package id 0" {
+		t.Errorf("left goes to the panel start, then the previous panel, got %q", selected())
+	}
+	if !strings.Contains(m.View(), selectionMarker+"Package id 0") {
+		t.Errorf("view did not scroll back up (scroll %d)", m.scroll)
+	}
+	press("up")
+	if selected() != "Composite" {
+		t.Errorf("up from the first row wraps to the last, got %q", selected())
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if strings.Contains(m.View(), selectionMarker) {
+		t.Error("esc should clear the selection")
+	}
+}
+
+func TestSearchAndPin(t *testing.T) {
+	m := testModel(120)
+	var saved []string
+	m.savePins = func(keys []string) error { saved = keys; return nil }
+	typeKeys := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			next, _ := m.Update(k)
+			m = next.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	typeKeys(runes("/"), runes("N"), runes("V"), runes("m"))
+	if r, ok := m.selectedReading(); !ok || r.Chip != "nvme-pci-0300" {
+		t.Fatalf("search selected %+v, want the NVMe sensor", r)
+	}
+	// While searching, keys are text: t must not pin yet
+	typeKeys(tea.KeyMsg{Type: tea.KeyEnter}, runes("t"))
+	if want := []string{"nvme-pci-0300/Composite"}; !slices.Equal(saved, want) {
+		t.Fatalf("saved %q, want %q", saved, want)
+	}
+
+	panels := m.renderSensorPanels(120)
+	if len(panels) != 2 || !strings.Contains(panels[0], "Pinned") || !strings.Contains(panels[0], "Composite") {
+		t.Fatalf("first panel should pin the NVMe sensor:\n%s", panels[0])
+	}
+	if strings.Contains(panels[1], "Composite") {
+		t.Error("pinned sensor should leave its own panel")
+	}
+	if r, _ := m.selectedReading(); r.Key() != "nvme-pci-0300/Composite" || m.selected != 0 {
+		t.Errorf("selection should follow the pinned row, got %+v at %d", r, m.selected)
+	}
+
+	// Pinned sensors stay on top when grouped by component too
+	typeKeys(runes("g"))
+	if panels := m.renderSensorPanels(120); !strings.Contains(panels[0], "Pinned") {
+		t.Error("grouping should keep the pinned panel first")
+	}
+
+	typeKeys(runes("t"))
+	if len(saved) != 0 || strings.Contains(strings.Join(m.renderSensorPanels(120), "\n"), "Pinned") {
+		t.Errorf("t again should unpin, saved %q", saved)
+	}
+}
+
+func TestSourceWarning(t *testing.T) {
+	m := testModel(160)
+	m.detector = alert.NewDetector()
+	failed := []*sensor.SourceError{{Source: "nvidia-smi", Err: errors.New("exit status 9")}}
+	next, _ := m.Update(sensorDataMsg{readings: m.readings, time: time.Now(), failed: failed})
+	m = next.(Model)
+
+	view := m.View()
+	if !strings.Contains(view, "nvidia-smi: exit status 9") {
+		t.Error("failed source not shown in the title bar")
+	}
+	if strings.Contains(view, "ERROR") || !strings.Contains(view, "Package id 0") {
+		t.Error("a failed source should not replace the sensors that read")
+	}
+
+	// The warning clears once the source reads again
+	next, _ = m.Update(sensorDataMsg{readings: m.readings, time: time.Now()})
+	if strings.Contains(next.(Model).View(), "nvidia-smi") {
+		t.Error("warning kept after the source recovered")
+	}
+}
+
+func TestCollectingSparkline(t *testing.T) {
+	m := testModel(160)
+	m.detector = alert.NewDetector()
+	fresh := sensor.Reading{Chip: "k10temp-pci-00c3", Adapter: "PCI adapter", Label: "Tctl", Temp: 48}
+	next, _ := m.Update(sensorDataMsg{readings: append(m.readings, fresh), time: time.Now()})
+	m = next.(Model)
+
+	var row string
+	for _, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, "Tctl") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "collecting…") || strings.Contains(row, " pk") {
+		t.Errorf("one-point sensor row should say collecting without stats:\n%s", row)
+	}
+	if !strings.Contains(m.View(), " pk") {
+		t.Error("sensors with history lost their stats")
+	}
+}
+
+func TestSuspectNotPeak(t *testing.T) {
+	m := testModel(120)
+	m.detector = alert.NewDetector()
+	m.peaks = store.Peaks{}
+	m.session = store.Peaks{}
+	m.history.OutlierDelta = 10
+	r := sensor.Reading{Chip: "k10temp-pci-00c3", Label: "Tctl", Temp: 50}
+	poll := func(temp float64) {
+		r.Temp = temp
+		next, _ := m.Update(sensorDataMsg{readings: []sensor.Reading{r}, time: time.Now()})
+		m = next.(Model)
+	}
+	for i := 0; i < 5; i++ {
+		poll(50)
+	}
+	poll(127.5) // a bus glitch
+	if p := m.peaks[r.Key()]; p.Temp != 50 {
+		t.Errorf("all-time peak = %.1f, want the glitch left out", p.Temp)
+	}
+	if p := m.session[r.Key()]; p.Temp != 50 {
+		t.Errorf("session peak = %.1f, want the glitch left out", p.Temp)
+	}
+}
+
+func TestQuitSavePeaksError(t *testing.T) {
+	// A file where the data directory should be makes the save fail
+	dir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	store.SetDataDir(dir)
+	defer store.SetDataDir("")
+
+	m := testModel(100)
+	m.cancel = func() {}
+	m.peaks = store.Peaks{}
+	tm, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if err := tm.(Model).Err(); err == nil || !strings.Contains(err.Error(), "saving peaks") {
+		t.Errorf("quit with an unwritable data directory: got %v", err)
+	}
+}
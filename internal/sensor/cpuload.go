@@ -0,0 +1,102 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// procStatPath is replaceable in tests.
+var procStatPath = "/proc/stat"
+
+// cpuLoadChip groups with the CPU temperatures under FriendlyName, so
+// load and temperature sit side by side in the grouped monitor.
+const cpuLoadChip = "cpu-load"
+
+// cpuTimes are the aggregate jiffy counters from the "cpu" line of
+// /proc/stat.
+type cpuTimes struct {
+	idle, total uint64
+}
+
+// parseProcStat reads the aggregate CPU counters. iowait counts as idle,
+// since a CPU waiting on disk is not doing work that heats it.
+func parseProcStat(data []byte) (cpuTimes, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		var t cpuTimes
+		for i, f := range fields[1:] {
+			// guest and guest_nice are already included in user and nice
+			if i >= 8 {
+				break
+			}
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return cpuTimes{}, fmt.Errorf("/proc/stat: %w", err)
+			}
+			t.total += v
+			if i == 3 || i == 4 {
+				t.idle += v
+			}
+		}
+		return t, nil
+	}
+	return cpuTimes{}, fmt.Errorf("/proc/stat: no cpu line")
+}
+
+// cpuLoadSource reports overall CPU utilization since its previous read.
+// It is Linux-only and off unless EnableCPULoad is called. The first read
+// has nothing to compare against and returns no reading.
+type cpuLoadSource struct {
+	mu   sync.Mutex
+	prev cpuTimes
+	ok   bool
+}
+
+func (*cpuLoadSource) Name() string { return string(SourceProcStat) }
+
+func (s *cpuLoadSource) Read(ctx context.Context) ([]Reading, error) {
+	data, err := os.ReadFile(procStatPath)
+	if err != nil {
+		return nil, nil // not Linux, or no procfs
+	}
+	cur, err := parseProcStat(data)
+	if err != nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	prev, ok := s.prev, s.ok
+	s.prev, s.ok = cur, true
+	s.mu.Unlock()
+	if !ok || cur.total <= prev.total {
+		return nil, nil
+	}
+
+	busy := 1 - float64(cur.idle-prev.idle)/float64(cur.total-prev.total)
+	return []Reading{{
+		Chip:    cpuLoadChip,
+		Adapter: "/proc/stat",
+		Label:   "Load",
+		Temp:    math.Round(busy*1000) / 10,
+		Unit:    "%",
+		Source:  SourceProcStat,
+	}}, nil
+}
+
+var enableCPULoad sync.Once
+
+// EnableCPULoad adds a CPU utilization reading, sampled from /proc/stat
+// between polls, to every ReadAll after the first.
+func EnableCPULoad() {
+	enableCPULoad.Do(func() {
+		readers = append(readers, &cpuLoadSource{})
+	})
+}
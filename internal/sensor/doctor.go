@@ -0,0 +1,85 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// SourceReport describes what one discovery source found.
+type SourceReport struct {
+	Name      string // e.g. "lm-sensors (json)"
+	Tool      string // executable or sysfs path that was checked
+	Available bool
+	Count     int
+	Err       error
+	Hint      string // how to fix a partial failure, e.g. missing permissions
+}
+
+// Diagnosis is the result of running every source individually.
+type Diagnosis struct {
+	Sources      []SourceReport
+	SkippedChips []string // `sensors -j` chips with no temperature input
+	Total        int      // merged reading count, as ReadAll would return
+}
+
+// Diagnose runs each source separately and reports availability, counts
+// and errors, mirroring the merge order and text fallback of ReadAll.
+func Diagnose() Diagnosis {
+	var d Diagnosis
+	ctx := context.Background()
+
+	lm := SourceReport{Name: "lm-sensors (json)", Tool: "sensors", Available: hasTool("sensors")}
+	jsonOK := false
+	if lm.Available {
+		out, err := runCommand(ctx, "sensors", "-j")
+		if err != nil {
+			lm.Err = err
+		} else {
+			readings, skipped, err := parseSensorsJSON(out)
+			lm.Count = len(readings)
+			lm.Err = err
+			d.SkippedChips = skipped
+			jsonOK = err == nil
+		}
+	}
+	d.add(lm)
+
+	if lm.Available && !jsonOK {
+		text := SourceReport{Name: "lm-sensors (text)", Tool: "sensors", Available: true}
+		readings, err := readSensorsText(ctx)
+		text.Count = len(readings)
+		text.Err = err
+		d.add(text)
+	}
+
+	nvidia, err := readNvidiaGPU(ctx)
+	d.add(SourceReport{Name: "nvidia-smi", Tool: "nvidia-smi", Available: hasTool("nvidia-smi"), Count: len(nvidia), Err: err})
+	amd, err := readAMDGPU(ctx)
+	d.add(SourceReport{Name: "rocm-smi", Tool: "rocm-smi", Available: hasTool("rocm-smi"), Count: len(amd), Err: err})
+	d.add(SourceReport{Name: "drivetemp", Tool: "/sys/module/drivetemp", Available: pathExists("/sys/module/drivetemp"), Count: len(readDrivetempHwmon())})
+	smart := SourceReport{Name: "smartctl", Tool: "smartctl", Available: hasTool("smartctl")}
+	smartReadings, denied := scanSmartctl(ctx)
+	smart.Count = len(smartReadings)
+	if denied > 0 {
+		smart.Hint = fmt.Sprintf("%d drive(s) could not be opened: %s", denied, smartPermissionHint)
+	}
+	d.add(smart)
+
+	return d
+}
+
+func (d *Diagnosis) add(r SourceReport) {
+	d.Sources = append(d.Sources, r)
+	d.Total += r.Count
+}
+
+func hasTool(name string) bool {
+	_, err := lookPath(name)
+	return err == nil
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
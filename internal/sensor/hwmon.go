@@ -0,0 +1,73 @@
+package sensor
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hwmonRoot is the sysfs class directory of hwmon devices; tests point it
+// at a fake tree.
+var hwmonRoot = "/sys/class/hwmon"
+
+// genericLabelRe matches the raw feature names lm-sensors falls back to
+// when it has no label for an input, e.g. "temp3".
+var genericLabelRe = regexp.MustCompile(`^temp\d+$`)
+
+// applyHwmonLabels replaces generic "tempN" labels with the chip's
+// tempN_label from sysfs, so Super I/O chips such as nct6775 show
+// "CPUTIN" or "SYSTIN" instead of temp1, temp2. Only live readings go
+// through it: a dump from another machine must keep its own labels.
+func applyHwmonLabels(readings []Reading) {
+	cache := make(map[string]map[string]string)
+	for i, r := range readings {
+		if !genericLabelRe.MatchString(r.Label) {
+			continue
+		}
+		labels, ok := cache[r.Chip]
+		if !ok {
+			labels = hwmonLabels(r.Chip)
+			cache[r.Chip] = labels
+		}
+		if l := labels[r.Label]; l != "" {
+			readings[i].Label = l
+		}
+	}
+}
+
+// hwmonLabels reads the tempN_label files of the hwmon device behind an
+// lm-sensors chip name ("nct6775-isa-0290" is the device named nct6775).
+// Nothing is returned when several devices share the name, since the
+// labels could come from the wrong one.
+func hwmonLabels(chip string) map[string]string {
+	name, _, _ := strings.Cut(chip, "-")
+	matches, _ := filepath.Glob(filepath.Join(hwmonRoot, "hwmon*", "name"))
+	dir := ""
+	for _, namePath := range matches {
+		data, err := os.ReadFile(namePath)
+		if err != nil || strings.TrimSpace(string(data)) != name {
+			continue
+		}
+		if dir != "" {
+			return nil
+		}
+		dir = filepath.Dir(namePath)
+	}
+	if dir == "" {
+		return nil
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "temp*_label"))
+	labels := make(map[string]string, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		if l := strings.TrimSpace(string(data)); l != "" {
+			labels[strings.TrimSuffix(filepath.Base(f), "_label")] = l
+		}
+	}
+	return labels
+}
@@ -1,15 +1,23 @@
 package sensor
 
-import "strings"
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// ChipIdentity maps chip IDs starting with Prefix to a component name.
+type ChipIdentity struct {
+	Prefix string
+	Name   string
+}
 
 // chipIdentityMap maps chip name prefixes to friendly component names.
-var chipIdentityMap = []struct {
-	prefix string
-	name   string
-}{
+var chipIdentityMap = []ChipIdentity{
 	{"coretemp", "CPU"},
 	{"k10temp", "CPU"},
 	{"zenpower", "CPU"},
+	{"cpu-load", "CPU"},
 	{"amdgpu", "GPU (AMD)"},
 	{"radeon", "GPU (AMD)"},
 	{"nouveau", "GPU (NVIDIA)"},
@@ -24,12 +32,15 @@ var chipIdentityMap = []struct {
 	{"ath", "WiFi"},
 	{"mt7", "WiFi"},
 	{"rtw", "WiFi"},
+	{"spd5118", "Memory (DIMM)"},
 	{"pch", "PCH (Chipset)"},
 	{"acpi", "ACPI Thermal"},
 	{"it87", "Motherboard"},
 	{"nct", "Motherboard"},
 	{"w83", "Motherboard"},
 	{"f71", "Motherboard"},
+	{"gigabyte_wmi", "Motherboard"},
+	{"asusec", "Motherboard"},
 	{"asus", "Motherboard"},
 	{"thinkpad", "Laptop EC"},
 	{"dell", "Laptop EC"},
@@ -37,13 +48,40 @@ var chipIdentityMap = []struct {
 	{"bat", "Battery"},
 }
 
-// FriendlyName returns a human-readable component name for a chip ID.
+// userChipNames are the config's [chips] entries, checked before the
+// built-in map.
+var userChipNames []ChipIdentity
+
+// SetChipNames adds user prefix-to-component mappings, e.g. for a
+// motherboard chip the built-in map does not know. They are listed longest
+// prefix first.
+func SetChipNames(names map[string]string) {
+	userChipNames = userChipNames[:0]
+	for prefix, name := range names {
+		userChipNames = append(userChipNames, ChipIdentity{strings.ToLower(prefix), name})
+	}
+	slices.SortFunc(userChipNames, func(a, b ChipIdentity) int {
+		return cmp.Or(len(b.Prefix)-len(a.Prefix), strings.Compare(a.Prefix, b.Prefix))
+	})
+}
+
+// IdentityMap returns the user mappings followed by the built-in ones.
+func IdentityMap() []ChipIdentity {
+	return slices.Concat(userChipNames, chipIdentityMap)
+}
+
+// FriendlyName returns a human-readable component name for a chip ID. The
+// longest matching prefix wins, so a specific entry applies wherever it
+// sits in the map; on a tie a user mapping beats the built-in one.
 func FriendlyName(chip string) string {
 	lower := strings.ToLower(chip)
-	for _, entry := range chipIdentityMap {
-		if strings.HasPrefix(lower, entry.prefix) {
-			return entry.name
+	name, best := "Sensor", 0
+	for _, entries := range [][]ChipIdentity{userChipNames, chipIdentityMap} {
+		for _, entry := range entries {
+			if len(entry.Prefix) > best && strings.HasPrefix(lower, entry.Prefix) {
+				name, best = entry.Name, len(entry.Prefix)
+			}
 		}
 	}
-	return "Sensor"
+	return name
 }
@@ -1,8 +1,9 @@
 package sensor
 
 import (
+	"context"
 	"encoding/json"
-	"os/exec"
+	"errors"
 	"regexp"
 	"sort"
 	"strconv"
@@ -10,39 +11,72 @@ import (
 )
 
 // ReadAll dynamically discovers all available temperature sensors by
-// combining: (1) `sensors -j` JSON output, (2) nvidia-smi, (3) drive temps.
-// New sensors appearing at runtime are picked up automatically.
+// merging every registered Reader: (1) `sensors -j` JSON output, (2)
+// nvidia-smi, (3) rocm-smi, (4) drive temps.
+// New sensors appearing at runtime are picked up automatically. Readings
+// without hardware thresholds get their component's defaults, see
+// SetComponentThresholds.
 func ReadAll() ([]Reading, error) {
-	readings, err := readSensorsJSON()
-	if err != nil {
-		// Fallback to text parsing if JSON fails (older lm-sensors)
-		readings, err = readSensorsText()
+	return ReadAllContext(context.Background())
+}
+
+// ReadAllContext is ReadAll with cancellation: every subprocess is started
+// with ctx, so a slow tool is killed and ReadAllContext returns ctx.Err()
+// as soon as the context is cancelled.
+//
+// A failing source does not hide the others: the readings that were read
+// come back together with an error joining a *SourceError per failed
+// source, see SourceErrors.
+func ReadAllContext(ctx context.Context) ([]Reading, error) {
+	readings, err := readAll(ctx, readers)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	applyComponentThresholds(readings)
+	return readings, err
+}
+
+func readAll(ctx context.Context, sources []Reader) ([]Reading, error) {
+	var readings []Reading
+	var errs []error
+	for _, src := range sources {
+		rs, err := src.Read(ctx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if err != nil {
-			return nil, err
+			errs = append(errs, &SourceError{Source: src.Name(), Err: err})
 		}
+		readings = append(readings, rs...)
 	}
-
-	// Merge NVIDIA GPU temps
-	readings = append(readings, ReadNvidiaGPU()...)
-
-	// Merge drive temps (drivetemp hwmon + smartctl)
-	readings = append(readings, ReadDriveTemps()...)
-
-	return readings, nil
+	return readings, errors.Join(errs...)
 }
 
 // ── JSON parser (primary) ────────────────────────────────────────────
 
 // readSensorsJSON parses `sensors -j` for fully dynamic sensor discovery.
-func readSensorsJSON() ([]Reading, error) {
-	out, err := exec.Command("sensors", "-j").Output()
+func readSensorsJSON(ctx context.Context) ([]Reading, error) {
+	out, err := runCommand(ctx, "sensors", "-j")
 	if err != nil {
 		return nil, err
 	}
+	return ParseSensorsJSON(out)
+}
 
+// ParseSensorsJSON parses `sensors -j` output, e.g. a dump attached to a
+// bug report, into readings exactly as the live lm-sensors source does.
+// Component fallback thresholds are not applied.
+func ParseSensorsJSON(raw []byte) ([]Reading, error) {
+	readings, _, err := parseSensorsJSON(raw)
+	return readings, err
+}
+
+// parseSensorsJSON parses raw `sensors -j` output. It also returns the
+// chips that were present but yielded no temperature readings.
+func parseSensorsJSON(out []byte) ([]Reading, []string, error) {
 	var data map[string]json.RawMessage
 	if err := json.Unmarshal(out, &data); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var readings []Reading
@@ -54,12 +88,15 @@ func readSensorsJSON() ([]Reading, error) {
 	}
 	sort.Strings(chipNames)
 
+	var skipped []string
 	for _, chipName := range chipNames {
 		chipRaw := data[chipName]
 		var chip map[string]json.RawMessage
 		if err := json.Unmarshal(chipRaw, &chip); err != nil {
+			skipped = append(skipped, chipName)
 			continue
 		}
+		before := len(readings)
 
 		adapter := ""
 		if raw, ok := chip["Adapter"]; ok {
@@ -76,56 +113,75 @@ func readSensorsJSON() ([]Reading, error) {
 		sort.Strings(labels)
 
 		for _, label := range labels {
-			sensorRaw := chip[label]
-
-			var fields map[string]float64
-			if err := json.Unmarshal(sensorRaw, &fields); err != nil {
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(chip[label], &raw); err != nil {
 				continue
 			}
 
-			// Find the temp_input field
-			var temp float64
-			var foundTemp bool
-			for k, v := range fields {
-				if strings.HasSuffix(k, "_input") && strings.Contains(k, "temp") {
-					temp = v
-					foundTemp = true
-					break
+			// Keep numeric subfeatures only; one non-numeric value must not
+			// drop the whole feature
+			fields := make(map[string]float64, len(raw))
+			keys := make([]string, 0, len(raw))
+			for k, v := range raw {
+				var f float64
+				if err := json.Unmarshal(v, &f); err == nil {
+					fields[k] = f
+					keys = append(keys, k)
 				}
 			}
-			if !foundTemp || temp < -200 {
-				continue
+			sort.Strings(keys)
+
+			// Each tempN_input is its own reading, with tempN_max/crit as
+			// its thresholds
+			var inputs []string
+			for _, k := range keys {
+				if strings.HasPrefix(k, "temp") && strings.HasSuffix(k, "_input") {
+					inputs = append(inputs, strings.TrimSuffix(k, "_input"))
+				}
 			}
 
-			r := Reading{
-				Chip:    chipName,
-				Adapter: adapter,
-				Label:   label,
-				Temp:    temp,
-			}
+			for _, prefix := range inputs {
+				temp := fields[prefix+"_input"]
+				if temp < -200 {
+					continue
+				}
+
+				r := Reading{
+					Chip:    chipName,
+					Adapter: adapter,
+					Label:   label,
+					Temp:    temp,
+					Source:  SourceLMSensors,
+				}
+				if len(inputs) > 1 {
+					r.Label = label + " " + prefix
+				}
 
-			for k, v := range fields {
-				if strings.HasSuffix(k, "_max") && v > 0 && v < 1000 {
+				if v, ok := fields[prefix+"_max"]; ok && validThreshold(v) {
 					r.High = v
 					r.HasHigh = true
 				}
-				if strings.HasSuffix(k, "_crit") && v > 0 && v < 1000 {
+				if v, ok := fields[prefix+"_crit"]; ok && validThreshold(v) {
 					r.Crit = v
 					r.HasCrit = true
 				}
+
+				readings = append(readings, r)
 			}
+		}
 
-			readings = append(readings, r)
+		if len(readings) == before {
+			skipped = append(skipped, chipName)
 		}
 	}
 
-	return readings, nil
+	return readings, skipped, nil
 }
 
 // ── Text parser (fallback) ───────────────────────────────────────────
 
-func readSensorsText() ([]Reading, error) {
-	out, err := exec.Command("sensors").Output()
+func readSensorsText(ctx context.Context) ([]Reading, error) {
+	out, err := runCommand(ctx, "sensors")
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +191,7 @@ func readSensorsText() ([]Reading, error) {
 var (
 	adapterRe  = regexp.MustCompile(`^Adapter:\s+(.+)$`)
 	namedValRe = regexp.MustCompile(`(\w+)\s*=\s*([+-]?\d+\.?\d*)°C`)
-	tempValRe  = regexp.MustCompile(`[+-]?(\d+\.?\d*)°C`)
+	tempValRe  = regexp.MustCompile(`([+-]?\d+\.?\d*)°C`)
 )
 
 // ParseSensorsText parses the human-readable `sensors` output.
@@ -156,6 +212,11 @@ func ParseSensorsText(output string) []Reading {
 			continue
 		}
 
+		// Wrapped threshold lines belong to the previous reading
+		if isContinuation(line) {
+			continue
+		}
+
 		if strings.Contains(line, "°C") {
 			idx := strings.Index(line, ":")
 			if idx < 0 {
@@ -173,31 +234,27 @@ func ParseSensorsText(output string) []Reading {
 			if err != nil || temp < -200 {
 				continue
 			}
-			// Check for negative sign
-			fullMatch := tempValRe.FindString(rest)
-			if strings.HasPrefix(strings.TrimSpace(fullMatch), "-") {
-				temp = -temp
-			}
 
 			r := Reading{
 				Chip:    currentChip,
 				Adapter: currentAdapter,
 				Label:   label,
 				Temp:    temp,
+				Source:  SourceLMSensors,
 			}
 
-			if high := extractNamedVal(line, "high"); high > 0 && high < 1000 {
+			if high := extractNamedVal(line, "high"); validThreshold(high) {
 				r.High = high
 				r.HasHigh = true
 			}
-			if crit := extractNamedVal(line, "crit"); crit > 0 && crit < 1000 {
+			if crit := extractNamedVal(line, "crit"); validThreshold(crit) {
 				r.Crit = crit
 				r.HasCrit = true
 			}
 			if i+1 < len(lines) {
 				next := strings.TrimRight(lines[i+1], "\r")
-				if strings.Contains(next, "crit") && !strings.Contains(next, ":") {
-					if crit := extractNamedVal(next, "crit"); crit > 0 && crit < 1000 {
+				if isContinuation(next) {
+					if crit := extractNamedVal(next, "crit"); validThreshold(crit) {
 						r.Crit = crit
 						r.HasCrit = true
 					}
@@ -217,6 +274,26 @@ func ParseSensorsText(output string) []Reading {
 	return readings
 }
 
+// isContinuation reports whether a line is a wrapped "(crit = ...)" style
+// threshold list: indented, and opening with a parenthesis rather than a
+// new "Label:" feature.
+func isContinuation(line string) bool {
+	if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(line), "(")
+}
+
+// maxThreshold is the highest high/crit threshold taken at face value.
+// Some chips report sentinels such as +65261.8°C for an unset limit, and
+// no real part throttles above 200°C.
+const maxThreshold = 200
+
+// validThreshold reports whether v is a plausible high/crit threshold.
+func validThreshold(v float64) bool {
+	return v > 0 && v <= maxThreshold
+}
+
 func extractNamedVal(line, name string) float64 {
 	matches := namedValRe.FindAllStringSubmatch(line, -1)
 	for _, m := range matches {
@@ -1,7 +1,14 @@
 package sensor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 const testSensorOutput = `iwlwifi_1-virtual-0
@@ -78,6 +85,15 @@ func TestParseSensors(t *testing.T) {
 		}
 	}
 
+	for _, r := range readings {
+		if r.Label == "Sensor 1" {
+			if r.HasHigh {
+				t.Errorf("NVMe Sensor 1: sentinel high %f not dropped", r.High)
+			}
+			break
+		}
+	}
+
 	for _, r := range readings {
 		t.Logf("%-30s %-20s %6.1f°C  high=%.1f(has=%v) crit=%.1f(has=%v)",
 			r.Chip, r.Label, r.Temp, r.High, r.HasHigh, r.Crit, r.HasCrit)
@@ -97,6 +113,9 @@ func TestFriendlyName(t *testing.T) {
 		{"nvidia-gpu-0", "GPU (NVIDIA)"},
 		{"smart-sda", "HDD/SSD"},
 		{"drivetemp-hwmon4", "HDD/SSD"},
+		{"spd5118-i2c-1-50", "Memory (DIMM)"},
+		{"gigabyte_wmi-virtual-0", "Motherboard"},
+		{"asusec-isa-000a", "Motherboard"},
 		{"some-unknown-chip", "Sensor"},
 	}
 	for _, tt := range tests {
@@ -106,3 +125,620 @@ func TestFriendlyName(t *testing.T) {
 		}
 	}
 }
+
+func TestFriendlyNameLongestPrefix(t *testing.T) {
+	defer func(saved []ChipIdentity) { chipIdentityMap = saved }(chipIdentityMap)
+
+	// The more specific prefix wins whichever way round the map lists them
+	for _, m := range [][]ChipIdentity{
+		{{"nvidia", "GPU"}, {"nvidia-gpu", "GPU die"}},
+		{{"nvidia-gpu", "GPU die"}, {"nvidia", "GPU"}},
+	} {
+		chipIdentityMap = m
+		if got := FriendlyName("nvidia-gpu-0"); got != "GPU die" {
+			t.Errorf("%v: nvidia-gpu-0 = %q, want GPU die", m, got)
+		}
+		if got := FriendlyName("nvidia-smi-0"); got != "GPU" {
+			t.Errorf("%v: nvidia-smi-0 = %q, want GPU", m, got)
+		}
+	}
+
+	// A short user prefix does not shadow a longer built-in one
+	chipIdentityMap = []ChipIdentity{{"nvidia-gpu", "GPU die"}}
+	defer SetChipNames(nil)
+	SetChipNames(map[string]string{"nv": "Accelerator"})
+	if got := FriendlyName("nvidia-gpu-0"); got != "GPU die" {
+		t.Errorf("nvidia-gpu-0 = %q, want the built-in GPU die", got)
+	}
+	if got := FriendlyName("nvswitch-0"); got != "Accelerator" {
+		t.Errorf("nvswitch-0 = %q, want Accelerator", got)
+	}
+}
+
+func TestSetChipNames(t *testing.T) {
+	defer SetChipNames(nil)
+	SetChipNames(map[string]string{
+		"IT8689":        "Motherboard",
+		"nvme":          "Boot drive",
+		"nvme-pci-0400": "Scratch drive",
+	})
+
+	tests := []struct {
+		chip string
+		want string
+	}{
+		{"it8689-isa-0a40", "Motherboard"},
+		{"nvme-pci-0300", "Boot drive"},    // user entry ahead of the built-in
+		{"nvme-pci-0400", "Scratch drive"}, // longest prefix wins
+		{"coretemp-isa-0000", "CPU"},
+	}
+	for _, tt := range tests {
+		if got := FriendlyName(tt.chip); got != tt.want {
+			t.Errorf("FriendlyName(%q) = %q, want %q", tt.chip, got, tt.want)
+		}
+	}
+	if m := IdentityMap(); m[0].Prefix != "nvme-pci-0400" || len(m) != len(chipIdentityMap)+3 {
+		t.Errorf("IdentityMap starts %v with %d entries", m[0], len(m))
+	}
+}
+
+const testNvidiaQ = `
+==============NVSMI LOG==============
+
+Attached GPUs                             : 2
+GPU 00000000:01:00.0
+    Temperature
+        GPU Current Temp                  : 45 C
+        GPU Shutdown Temp                 : 98 C
+        GPU Slowdown Temp                 : 95 C
+        GPU Max Operating Temp            : 93 C
+        Memory Current Temp               : 60 C
+        Memory Max Operating Temp         : 105 C
+
+GPU 00000000:02:00.0
+    Temperature
+        GPU Current Temp                  : 50 C
+        GPU Shutdown Temp                 : 96 C
+        GPU Slowdown Temp                 : 93 C
+        Memory Current Temp               : N/A
+`
+
+func TestParseNvidiaPerGPU(t *testing.T) {
+	thresh := parseNvidiaThresholds(testNvidiaQ)
+	if len(thresh) != 2 {
+		t.Fatalf("expected 2 GPU sections, got %d", len(thresh))
+	}
+
+	readings := parseNvidiaQuery("0, NVIDIA GeForce RTX 3090, 45, 60\n1, NVIDIA GeForce RTX 3090, 50, N/A\n", thresh)
+	if len(readings) != 3 {
+		t.Fatalf("expected 3 readings, got %d", len(readings))
+	}
+
+	if r := readings[0]; r.Chip != "nvidia-gpu-0" || r.High != 95 || r.Crit != 98 {
+		t.Errorf("GPU 0 core: got %+v", r)
+	}
+	if r := readings[1]; r.Label != "GPU Mem" || r.Temp != 60 || !r.HasHigh || r.High != 105 {
+		t.Errorf("GPU 0 mem: got %+v", r)
+	}
+	if r := readings[2]; r.Chip != "nvidia-gpu-1" || r.High != 93 || r.Crit != 96 {
+		t.Errorf("GPU 1 core: got %+v", r)
+	}
+}
+
+func TestParseRocmSMI(t *testing.T) {
+	raw := []byte(`{"card0": {"Temperature (Sensor edge) (C)": "45.0", "Temperature (Sensor junction) (C)": "52.0", "Temperature (Sensor memory) (C)": "60.0", "Average Graphics Package Power (W)": "31.5", "Fan speed (%)": "20", "Fan RPM": "1100"}}`)
+
+	readings := parseRocmSMI(raw)
+	if len(readings) != 5 {
+		t.Fatalf("expected 5 readings, got %d: %+v", len(readings), readings)
+	}
+
+	byLabel := make(map[string]Reading)
+	for _, r := range readings {
+		if r.Chip != "amdgpu-rocm-0" {
+			t.Errorf("chip: got %q, want amdgpu-rocm-0", r.Chip)
+		}
+		byLabel[r.Label] = r
+	}
+	if r := byLabel["junction"]; r.Temp != 52 || r.Unit != "" {
+		t.Errorf("junction: got %+v", r)
+	}
+	if r := byLabel["Power"]; r.Temp != 31.5 || r.Unit != "W" {
+		t.Errorf("power: got %+v", r)
+	}
+	if r := byLabel["Fan"]; r.Temp != 1100 || r.Unit != "RPM" {
+		t.Errorf("fan: got %+v", r)
+	}
+}
+
+// testSensorJSON is testSensorOutput as `sensors -j` prints it, plus a chip
+// without an Adapter key and one without temperature features.
+const testSensorJSON = `{
+   "iwlwifi_1-virtual-0":{
+      "Adapter": "Virtual device",
+      "temp1":{"temp1_input": 35.000}
+   },
+   "nvme-pci-0300":{
+      "Adapter": "PCI adapter",
+      "Composite":{"temp1_input": 36.850, "temp1_max": 81.850, "temp1_min": -273.150, "temp1_crit": 84.850, "temp1_alarm": 0.000},
+      "Sensor 1":{"temp2_input": 36.850, "temp2_max": 65261.850, "temp2_min": -273.150},
+      "Sensor 2":{"temp3_input": 49.850, "temp3_max": 65261.850, "temp3_min": -273.150}
+   },
+   "coretemp-isa-0000":{
+      "Adapter": "ISA adapter",
+      "Package id 0":{"temp1_input": 48.000, "temp1_max": 101.000, "temp1_crit": 115.000, "temp1_crit_alarm": 0.000},
+      "Core 0":{"temp2_input": 46.000, "temp2_max": 101.000, "temp2_crit": 115.000, "temp2_crit_alarm": 0.000}
+   },
+   "acpitz-acpi-0":{
+      "temp1":{"temp1_input": 27.800}
+   },
+   "nct6798-isa-0290":{
+      "Adapter": "ISA adapter",
+      "in0":{"in0_input": 0.304, "in0_min": 0.000, "in0_max": 1.744},
+      "fan1":{"fan1_input": 0.000, "fan1_min": 0.000}
+   }
+}`
+
+func TestParseSensorsJSON(t *testing.T) {
+	readings, err := ParseSensorsJSON([]byte(testSensorJSON))
+	if err != nil {
+		t.Fatalf("ParseSensorsJSON: %v", err)
+	}
+	byKey := make(map[string]Reading, len(readings))
+	for _, r := range readings {
+		byKey[r.Key()] = r
+		if r.Source != SourceLMSensors {
+			t.Errorf("%s: source %q, want lm-sensors", r.Key(), r.Source)
+		}
+	}
+	if len(readings) != 7 {
+		t.Errorf("expected 7 readings, got %d: %+v", len(readings), readings)
+	}
+
+	core := byKey["coretemp-isa-0000/Core 0"]
+	if core.Temp != 46.0 || core.High != 101.0 || core.Crit != 115.0 || !core.HasHigh || !core.HasCrit {
+		t.Errorf("Core 0: got %+v", core)
+	}
+	if core.Adapter != "ISA adapter" {
+		t.Errorf("Core 0 adapter: got %q", core.Adapter)
+	}
+
+	if c := byKey["nvme-pci-0300/Composite"]; !c.HasHigh || c.High != 81.85 || !c.HasCrit || c.Crit != 84.85 {
+		t.Errorf("NVMe Composite: got %+v", c)
+	}
+	// 65261.85 is the NVMe "no limit" sentinel, not a threshold
+	for _, label := range []string{"Sensor 1", "Sensor 2"} {
+		if r := byKey["nvme-pci-0300/"+label]; r.HasHigh {
+			t.Errorf("NVMe %s: sentinel high %f not dropped", label, r.High)
+		}
+	}
+
+	acpi, ok := byKey["acpitz-acpi-0/temp1"]
+	if !ok || acpi.Adapter != "" || acpi.Temp != 27.8 {
+		t.Errorf("chip without adapter: got %+v (found=%v)", acpi, ok)
+	}
+
+	for key := range byKey {
+		if strings.HasPrefix(key, "nct6798") {
+			t.Errorf("chip without temperature features produced %s", key)
+		}
+	}
+
+	if _, err := ParseSensorsJSON([]byte("sensors: no sensors found")); err == nil {
+		t.Error("expected an error for non-JSON input")
+	}
+}
+
+func TestParseSensorsJSONSkippedChips(t *testing.T) {
+	raw := []byte(`{
+		"coretemp-isa-0000": {"Adapter": "ISA adapter", "Core 0": {"temp2_input": 46.0, "temp2_max": 101.0, "temp2_crit": 115.0}},
+		"BAT0-acpi-0": {"Adapter": "ACPI interface", "in0": {"in0_input": 12.9}}
+	}`)
+
+	readings, skipped, err := parseSensorsJSON(raw)
+	if err != nil {
+		t.Fatalf("parseSensorsJSON: %v", err)
+	}
+	if len(readings) != 1 || readings[0].Label != "Core 0" {
+		t.Errorf("expected one Core 0 reading, got %+v", readings)
+	}
+	if len(skipped) != 1 || skipped[0] != "BAT0-acpi-0" {
+		t.Errorf("expected BAT0-acpi-0 skipped, got %v", skipped)
+	}
+}
+
+func TestParseSensorsTextWrappedCrit(t *testing.T) {
+	const output = `nvme-pci-0300
+Adapter: PCI adapter
+Composite:    +36.9°C  (low  = -273.1°C, high = +81.8°C)
+                       (crit = +84.8°C, alarm: 0)
+Sensor 1:     +36.9°C  (low  = -273.1°C, high = +65.0°C)
+Sensor 2:     +49.9°C  (low  = -273.1°C, crit = +90.0°C)
+`
+	readings := ParseSensorsText(output)
+	if len(readings) != 3 {
+		t.Fatalf("expected 3 readings, got %d: %+v", len(readings), readings)
+	}
+
+	composite := readings[0]
+	if !composite.HasCrit || composite.Crit != 84.8 {
+		t.Errorf("Composite crit: got %f (has=%v), want 84.8", composite.Crit, composite.HasCrit)
+	}
+
+	sensor1 := readings[1]
+	if sensor1.Label != "Sensor 1" || sensor1.HasCrit {
+		t.Errorf("Sensor 1 must not inherit the next label's crit: got %+v", sensor1)
+	}
+}
+
+func TestParseSensorsTextNegative(t *testing.T) {
+	const output = `outdoor-virtual-0
+Adapter: Virtual device
+temp1:         -5.0°C  (high = +40.0°C)
+temp2:         +3.5°C
+`
+	readings := ParseSensorsText(output)
+	if len(readings) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(readings))
+	}
+	if readings[0].Temp != -5.0 {
+		t.Errorf("temp1: got %f, want -5.0", readings[0].Temp)
+	}
+	if readings[1].Temp != 3.5 {
+		t.Errorf("temp2: got %f, want 3.5", readings[1].Temp)
+	}
+}
+
+func TestParseSensorsJSONMultiInput(t *testing.T) {
+	raw := []byte(`{
+		"nct6798-isa-0290": {
+			"Adapter": "ISA adapter",
+			"SYSTIN": {"temp2_input": 41.0, "temp1_input": 35.0, "temp1_max": 80.0, "temp2_crit": 95.0, "temp1_type": "thermistor"}
+		}
+	}`)
+
+	for i := 0; i < 5; i++ {
+		readings, _, err := parseSensorsJSON(raw)
+		if err != nil {
+			t.Fatalf("parseSensorsJSON: %v", err)
+		}
+		if len(readings) != 2 {
+			t.Fatalf("expected 2 readings, got %d: %+v", len(readings), readings)
+		}
+
+		r1, r2 := readings[0], readings[1]
+		if r1.Label != "SYSTIN temp1" || r1.Temp != 35.0 || r1.High != 80.0 || r1.HasCrit {
+			t.Errorf("temp1: got %+v", r1)
+		}
+		if r2.Label != "SYSTIN temp2" || r2.Temp != 41.0 || r2.HasHigh || r2.Crit != 95.0 {
+			t.Errorf("temp2: got %+v", r2)
+		}
+	}
+}
+
+func TestReadingSource(t *testing.T) {
+	for _, r := range ParseSensorsText(testSensorOutput) {
+		if r.Source != SourceLMSensors {
+			t.Fatalf("text reading %s: source %q, want %q", r.Key(), r.Source, SourceLMSensors)
+		}
+	}
+	for _, r := range parseNvidiaQuery("0, NVIDIA GeForce RTX 3090, 45, 60\n", nil) {
+		if r.Source != SourceNvidia {
+			t.Fatalf("nvidia reading %s: source %q, want %q", r.Key(), r.Source, SourceNvidia)
+		}
+	}
+}
+
+func TestReadAllContextCancel(t *testing.T) {
+	if _, err := os.Stat("/bin/sleep"); err != nil {
+		t.Skip("no /bin/sleep")
+	}
+
+	// A fake `sensors` that hangs, with nothing else on PATH
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec /bin/sleep 30\n"
+	if err := os.WriteFile(filepath.Join(dir, "sensors"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := ReadAllContext(ctx)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("ReadAllContext took %v after cancel", elapsed)
+	}
+}
+
+func TestParseSmartJSON(t *testing.T) {
+	nvme := []byte(`{
+  "smartctl": {"version": [7, 4], "exit_status": 64},
+  "device": {"name": "/dev/nvme1n1", "type": "sntasmedia", "protocol": "NVMe"},
+  "model_name": "Samsung SSD 980 PRO 1TB",
+  "temperature": {"current": 41},
+  "nvme_smart_health_information_log": {"temperature": 41}
+}`)
+	temp, model, ok := parseSmartJSON(nvme)
+	if !ok || temp != 41 || model != "Samsung SSD 980 PRO 1TB" {
+		t.Errorf("got (%v, %q, %v), want (41, Samsung SSD 980 PRO 1TB, true)", temp, model, ok)
+	}
+
+	// Permission denied: smartctl still prints JSON, but without a temperature
+	denied := []byte(`{
+  "smartctl": {
+    "exit_status": 2,
+    "messages": [{"string": "Smartctl open device: /dev/nvme1n1 failed: Permission denied", "severity": "error"}]
+  }
+}`)
+	if _, _, ok := parseSmartJSON(denied); ok {
+		t.Error("expected ok=false for permission error")
+	}
+	if _, _, ok := parseSmartJSON(nil); ok {
+		t.Error("expected ok=false for empty output")
+	}
+}
+
+// fakeReader is a Reader returning canned readings.
+type fakeReader struct {
+	name     string
+	readings []Reading
+	err      error
+}
+
+func (f fakeReader) Name() string { return f.name }
+
+func (f fakeReader) Read(ctx context.Context) ([]Reading, error) { return f.readings, f.err }
+
+func TestReadAllMergesReaders(t *testing.T) {
+	cpu := fakeReader{name: "cpu", readings: []Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}}
+	gpu := fakeReader{name: "gpu", readings: []Reading{{Chip: "nvidia-gpu-0", Label: "GPU Temp", Temp: 60}}}
+	none := fakeReader{name: "none"}
+
+	readings, err := readAll(context.Background(), []Reader{cpu, none, gpu})
+	if err != nil {
+		t.Fatalf("readAll: %v", err)
+	}
+	if len(readings) != 2 || readings[0].Chip != "coretemp-isa-0000" || readings[1].Chip != "nvidia-gpu-0" {
+		t.Errorf("readings not merged in reader order: %+v", readings)
+	}
+
+	broken := fakeReader{name: "broken", err: errors.New("sensors: not found")}
+	if _, err := readAll(context.Background(), []Reader{broken, gpu}); err == nil {
+		t.Error("reader error not returned")
+	}
+}
+
+// stubCommands replaces runCommand and lookPath for the test with canned
+// stdout keyed by the full command line; unknown commands fail. Devices
+// maps listDevices patterns to the device nodes to report.
+func stubCommands(t *testing.T, outputs map[string]string, devices map[string][]string) {
+	t.Helper()
+	origRun, origLook, origList := runCommand, lookPath, listDevices
+	t.Cleanup(func() { runCommand, lookPath, listDevices = origRun, origLook, origList })
+
+	runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		line := strings.Join(append([]string{name}, args...), " ")
+		out, ok := outputs[line]
+		if !ok {
+			return nil, fmt.Errorf("unexpected command %q", line)
+		}
+		return []byte(out), nil
+	}
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	listDevices = func(pattern string) ([]string, error) { return devices[pattern], nil }
+}
+
+func TestReadNvidiaGPU(t *testing.T) {
+	stubCommands(t, map[string]string{
+		"nvidia-smi --query-gpu=index,name,temperature.gpu,temperature.memory --format=csv,noheader,nounits": "0, NVIDIA GeForce RTX 3090, 45, 60\n1, NVIDIA GeForce RTX 3090, 50, N/A\n",
+		"nvidia-smi -q -d TEMPERATURE": testNvidiaQ,
+	}, nil)
+
+	readings := ReadNvidiaGPU()
+	if len(readings) != 3 {
+		t.Fatalf("expected 3 readings, got %d", len(readings))
+	}
+	if r := readings[0]; r.Temp != 45 || r.High != 95 || r.Crit != 98 || r.Source != SourceNvidia {
+		t.Errorf("GPU 0 core: got %+v", r)
+	}
+	if r := readings[2]; r.Chip != "nvidia-gpu-1" || r.Temp != 50 {
+		t.Errorf("GPU 1 core: got %+v", r)
+	}
+}
+
+const testSmartctlA = `smartctl 7.4 2023-08-01 r5530 [x86_64-linux-6.8.0] (local build)
+=== START OF READ SMART DATA SECTION ===
+SMART Attributes Data Structure revision number: 16
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  9 Power_On_Hours          0x0032   091   091   000    Old_age   Always       -       7932
+190 Airflow_Temperature_Cel 0x0022   066   052   040    Old_age   Always       -       34
+194 Temperature_Celsius     0x0022   034   048   000    Old_age   Always       -       34 (Min/Max 17/48)
+`
+
+func TestReadSmartctlDrives(t *testing.T) {
+	stubCommands(t, map[string]string{
+		"smartctl -A /dev/sdy": testSmartctlA,
+		"smartctl -i /dev/sdy": "Device Model:     Samsung SSD 870 EVO 1TB\nSerial Number:    S6P\n",
+	}, map[string][]string{
+		"/dev/sd?": {"/dev/sdy", "/dev/sdz"},
+	})
+
+	readings := readSmartctlDrives(context.Background())
+	if len(readings) != 1 {
+		t.Fatalf("expected 1 reading (sdz fails), got %d: %+v", len(readings), readings)
+	}
+	r := readings[0]
+	if r.Chip != "smart-sdy" || r.Temp != 34 || r.Adapter != "Samsung SSD 870 EVO 1TB" || r.Source != SourceSmartctl {
+		t.Errorf("unexpected reading: %+v", r)
+	}
+}
+
+func TestComponentThresholds(t *testing.T) {
+	defer SetComponentThresholds(nil)
+
+	readings := []Reading{
+		{Chip: "drivetemp-hwmon3", Label: "Drive Temp", Temp: 40},
+		{Chip: "iwlwifi_1-virtual-0", Label: "temp1", Temp: 45},
+		{Chip: "drivetemp-hwmon4", Label: "Drive Temp", Temp: 40, High: 65, HasHigh: true},
+		{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50},
+		{Chip: "spd5118-i2c-1-50", Label: "temp1", Temp: 42},
+		{Chip: "drivetemp-hwmon5", Label: "Drive Temp", Temp: 40, High: 50, HasHigh: true},
+		{Chip: "drivetemp-hwmon6", Label: "Drive Temp", Temp: 40, Crit: 52, HasCrit: true},
+	}
+	applyComponentThresholds(readings)
+	if r := readings[0]; r.High != 55 || r.Crit != 60 || !r.HasHigh || !r.HasCrit {
+		t.Errorf("drive defaults: got %+v", r)
+	}
+	if r := readings[1]; r.High != 80 || r.HasCrit {
+		t.Errorf("wifi defaults: got %+v", r)
+	}
+	// A default crit below the hardware high would invert the limits
+	if r := readings[2]; r.High != 65 || r.HasCrit {
+		t.Errorf("hardware high 65 with default crit 60: got %+v, want no crit", r)
+	}
+	if r := readings[5]; r.High != 50 || r.Crit != 60 || r.Crit < r.High {
+		t.Errorf("hardware high 50: got %+v, want default crit 60 above it", r)
+	}
+	if r := readings[6]; r.Crit != 52 || r.HasHigh {
+		t.Errorf("hardware crit 52 with default high 55: got %+v, want no high", r)
+	}
+	for _, r := range readings {
+		if r.HasHigh && r.HasCrit && r.Crit < r.High {
+			t.Errorf("%s: crit %.0f below high %.0f", r.Key(), r.Crit, r.High)
+		}
+	}
+	if r := readings[3]; r.HasHigh || r.HasCrit {
+		t.Errorf("CPU has no default: got %+v", r)
+	}
+	if r := readings[4]; r.High != 85 || r.Crit != 95 {
+		t.Errorf("DIMM defaults: got %+v", r)
+	}
+
+	SetComponentThresholds(map[string]Thresholds{"HDD/SSD": {High: 50, Crit: 60}, "WiFi": {}})
+	readings = []Reading{
+		{Chip: "smart-sda", Label: "Drive Temp", Temp: 40},
+		{Chip: "iwlwifi_1-virtual-0", Label: "temp1", Temp: 45},
+	}
+	applyComponentThresholds(readings)
+	if r := readings[0]; r.High != 50 {
+		t.Errorf("configured drive high: got %+v", r)
+	}
+	if r := readings[1]; r.HasHigh {
+		t.Errorf("empty entry should disable the wifi default: got %+v", r)
+	}
+}
+
+func TestCPULoadSource(t *testing.T) {
+	orig := procStatPath
+	defer func() { procStatPath = orig }()
+	procStatPath = filepath.Join(t.TempDir(), "stat")
+
+	write := func(cpu string) {
+		t.Helper()
+		data := cpu + "\ncpu0 1 2 3 4 5 6 7 8 0 0\nintr 12345\n"
+		if err := os.WriteFile(procStatPath, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var src cpuLoadSource
+	// user nice system idle iowait irq softirq steal guest guest_nice
+	write("cpu  100 0 100 700 100 0 0 0 50 0")
+	if rs, _ := src.Read(context.Background()); len(rs) != 0 {
+		t.Fatalf("first read has no previous sample, got %+v", rs)
+	}
+
+	// 200 jiffies pass: 120 busy, 60 idle, 20 iowait; guest is not counted twice
+	write("cpu  200 0 120 760 120 0 0 0 90 0")
+	rs, err := src.Read(context.Background())
+	if err != nil || len(rs) != 1 {
+		t.Fatalf("Read = %+v, %v", rs, err)
+	}
+	if r := rs[0]; r.Temp != 60 || r.Unit != "%" || FriendlyName(r.Chip) != "CPU" {
+		t.Errorf("got %+v, want 60%% load grouped with CPU", r)
+	}
+}
+
+func TestHwmonLabels(t *testing.T) {
+	root := t.TempDir()
+	orig := hwmonRoot
+	hwmonRoot = root
+	t.Cleanup(func() { hwmonRoot = orig })
+
+	write := func(path, data string) {
+		t.Helper()
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("hwmon0/name", "coretemp\n")
+	write("hwmon2/name", "nct6775\n")
+	write("hwmon2/temp1_label", "SYSTIN\n")
+	write("hwmon2/temp2_label", "CPUTIN\n")
+
+	stubCommands(t, map[string]string{
+		"sensors -j": `{
+			"nct6775-isa-0290": {"Adapter": "ISA adapter",
+				"temp1": {"temp1_input": 34.0},
+				"temp2": {"temp2_input": 41.5},
+				"temp3": {"temp3_input": 45.0}},
+			"coretemp-isa-0000": {"Adapter": "ISA adapter",
+				"Core 0": {"temp2_input": 46.0}}
+		}`,
+	}, nil)
+
+	readings, err := lmSensorsSource{}.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	var labels []string
+	for _, r := range readings {
+		labels = append(labels, r.Label)
+	}
+	want := "Core 0,SYSTIN,CPUTIN,temp3"
+	if got := strings.Join(labels, ","); got != want {
+		t.Errorf("labels = %s, want %s", got, want)
+	}
+
+	// A dump is parsed with the labels it carries
+	raw, _ := ParseSensorsJSON([]byte(`{"nct6775-isa-0290": {"temp1": {"temp1_input": 34.0}}}`))
+	if len(raw) != 1 || raw[0].Label != "temp1" {
+		t.Errorf("ParseSensorsJSON relabelled a dump: %+v", raw)
+	}
+
+	// Two devices with the same name are ambiguous
+	write("hwmon3/name", "nct6775\n")
+	write("hwmon3/temp1_label", "AUXTIN0\n")
+	if got := hwmonLabels("nct6775-isa-0290"); got != nil {
+		t.Errorf("ambiguous chip labels = %v, want none", got)
+	}
+}
+
+func TestReadAllPartial(t *testing.T) {
+	cpu := fakeReader{name: "lm-sensors", readings: []Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}}
+	gpu := fakeReader{name: "nvidia-smi", err: errors.New("exit status 9")}
+	amd := fakeReader{name: "rocm-smi", err: errors.New("signal: killed")}
+
+	readings, err := readAll(context.Background(), []Reader{cpu, gpu, amd})
+	if len(readings) != 1 || readings[0].Chip != "coretemp-isa-0000" {
+		t.Errorf("readings of the working source lost: %+v", readings)
+	}
+	failed := SourceErrors(err)
+	if len(failed) != 2 || failed[0].Source != "nvidia-smi" || failed[1].Error() != "rocm-smi: signal: killed" {
+		t.Errorf("SourceErrors = %v", failed)
+	}
+	if SourceErrors(nil) != nil {
+		t.Error("SourceErrors(nil) should be empty")
+	}
+}
@@ -0,0 +1,99 @@
+package sensor
+
+import (
+	"context"
+	"errors"
+)
+
+// Reader is one discovery backend. ReadAll runs every registered reader
+// in order and merges their readings, so a new backend only needs a
+// Reader and an entry in readers.
+type Reader interface {
+	Name() string
+	Read(ctx context.Context) ([]Reading, error)
+}
+
+// readers are the registered backends in merge order. A missing optional
+// tool yields no readings and no error; a tool that is installed but
+// fails reports a SourceError while the other readers carry on.
+var readers = []Reader{
+	lmSensorsSource{},
+	nvidiaSource{},
+	amdSource{},
+	driveSource{},
+}
+
+// lmSensorsSource parses `sensors -j`, falling back to the text output of
+// older lm-sensors releases. Inputs lm-sensors left as "tempN" get their
+// hwmon tempN_label.
+type lmSensorsSource struct{}
+
+func (lmSensorsSource) Name() string { return string(SourceLMSensors) }
+
+func (lmSensorsSource) Read(ctx context.Context) ([]Reading, error) {
+	readings, err := readSensorsJSON(ctx)
+	if err != nil && ctx.Err() == nil {
+		readings, err = readSensorsText(ctx)
+	}
+	if err == nil {
+		applyHwmonLabels(readings)
+	}
+	return readings, err
+}
+
+// nvidiaSource reads NVIDIA GPU core and memory temps via nvidia-smi.
+type nvidiaSource struct{}
+
+func (nvidiaSource) Name() string { return string(SourceNvidia) }
+
+func (nvidiaSource) Read(ctx context.Context) ([]Reading, error) {
+	return readNvidiaGPU(ctx)
+}
+
+// amdSource reads AMD GPU temps, power and fan via rocm-smi.
+type amdSource struct{}
+
+func (amdSource) Name() string { return string(SourceROCm) }
+
+func (amdSource) Read(ctx context.Context) ([]Reading, error) {
+	return readAMDGPU(ctx)
+}
+
+// driveSource reads drive temps from drivetemp hwmon and smartctl.
+type driveSource struct{}
+
+func (driveSource) Name() string { return "drives" }
+
+func (driveSource) Read(ctx context.Context) ([]Reading, error) {
+	return readDriveTemps(ctx), nil
+}
+
+// SourceError is one reader's failure. ReadAll joins one per failed
+// reader with errors.Join and still returns the other readers' readings.
+type SourceError struct {
+	Source string // Reader name, e.g. "nvidia-smi"
+	Err    error
+}
+
+func (e *SourceError) Error() string { return e.Source + ": " + e.Err.Error() }
+
+func (e *SourceError) Unwrap() error { return e.Err }
+
+// SourceErrors returns the per-reader failures in an error from ReadAll,
+// in reader order.
+func SourceErrors(err error) []*SourceError {
+	var errs []error
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = j.Unwrap()
+	} else if err != nil {
+		errs = []error{err}
+	}
+	var out []*SourceError
+	for _, e := range errs {
+		var se *SourceError
+		if errors.As(e, &se) {
+			out = append(out, se)
+		}
+	}
+	return out
+}
@@ -3,16 +3,31 @@
 // smartctl/drivetemp to produce a unified view of all thermal sensors.
 package sensor
 
+// Source identifies which discovery backend produced a reading.
+type Source string
+
+const (
+	SourceLMSensors Source = "lm-sensors"
+	SourceNvidia    Source = "nvidia-smi"
+	SourceROCm      Source = "rocm-smi"
+	SourceSmartctl  Source = "smartctl"
+	SourceDrivetemp Source = "drivetemp"
+	SourceHwmon     Source = "hwmon"
+	SourceProcStat  Source = "proc-stat"
+)
+
 // Reading represents a single temperature reading from a sensor.
 type Reading struct {
 	Chip    string  // e.g. "coretemp-isa-0000"
 	Adapter string  // e.g. "ISA adapter"
 	Label   string  // e.g. "Core 0"
-	Temp    float64 // current temperature in Celsius
+	Temp    float64 // current value; temperature in Celsius unless Unit is set
 	High    float64 // high threshold (0 if not available)
 	Crit    float64 // critical threshold (0 if not available)
 	HasHigh bool
 	HasCrit bool
+	Unit    string // "" for temperature, otherwise e.g. "W", "RPM", "%"
+	Source  Source // discovery backend that produced this reading
 }
 
 // Key returns a unique identifier for this sensor.
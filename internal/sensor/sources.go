@@ -1,35 +1,83 @@
 package sensor
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// ReadNvidiaGPU reads GPU temperatures via nvidia-smi.
-// Returns nil (no error) if nvidia-smi is not available.
+// commandWaitDelay bounds how long a cancelled command may keep its output
+// pipes open (e.g. a grandchild of a killed wrapper script).
+const commandWaitDelay = 500 * time.Millisecond
+
+// command builds an exec.Cmd that is killed when ctx is cancelled.
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	return cmd
+}
+
+// runCommand runs a tool and returns its stdout, which is kept even when
+// the tool exits non-zero. Every source goes through it (and lookPath and
+// listDevices), so tests can stub sensors, nvidia-smi and smartctl with
+// canned output.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return command(ctx, name, args...).Output()
+}
+
+var (
+	lookPath    = exec.LookPath
+	listDevices = filepath.Glob // device nodes such as /dev/sd?
+)
+
+// ReadNvidiaGPU reads GPU core and memory temperatures via nvidia-smi.
+// Thresholds are parsed per GPU so multi-GPU systems with different cards
+// get their own slowdown/shutdown limits.
+// Returns nil if nvidia-smi is not available or fails.
 func ReadNvidiaGPU() []Reading {
-	path, err := exec.LookPath("nvidia-smi")
+	readings, _ := readNvidiaGPU(context.Background())
+	return readings
+}
+
+// readNvidiaGPU is ReadNvidiaGPU with the error of an installed but
+// failing nvidia-smi, e.g. after a driver update without a reboot.
+func readNvidiaGPU(ctx context.Context) ([]Reading, error) {
+	path, err := lookPath("nvidia-smi")
 	if err != nil || path == "" {
-		return nil
+		return nil, nil
 	}
 
-	out, err := exec.Command("nvidia-smi",
-		"--query-gpu=index,name,temperature.gpu",
+	out, err := runCommand(ctx, "nvidia-smi",
+		"--query-gpu=index,name,temperature.gpu,temperature.memory",
 		"--format=csv,noheader,nounits",
-	).Output()
+	)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+
+	var thresholds []map[string]float64
+	if q, err := runCommand(ctx, "nvidia-smi", "-q", "-d", "TEMPERATURE"); err == nil {
+		thresholds = parseNvidiaThresholds(string(q))
 	}
 
-	thresholds := parseNvidiaThresholds()
+	return parseNvidiaQuery(string(out), thresholds), nil
+}
 
+// parseNvidiaQuery parses the CSV output of nvidia-smi --query-gpu. The
+// thresholds slice is indexed by GPU position in the -q output, which
+// follows the same ordering as the index column.
+func parseNvidiaQuery(output string, thresholds []map[string]float64) []Reading {
 	var readings []Reading
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		parts := strings.SplitN(line, ", ", 3)
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.Split(line, ", ")
 		if len(parts) < 3 {
 			continue
 		}
@@ -41,6 +89,11 @@ func ReadNvidiaGPU() []Reading {
 			continue
 		}
 
+		var gpuThresh map[string]float64
+		if i < len(thresholds) {
+			gpuThresh = thresholds[i]
+		}
+
 		chipName := fmt.Sprintf("nvidia-gpu-%s", idx)
 
 		r := Reading{
@@ -48,18 +101,38 @@ func ReadNvidiaGPU() []Reading {
 			Adapter: name,
 			Label:   "GPU Temp",
 			Temp:    temp,
+			Source:  SourceNvidia,
 		}
-
-		if t, ok := thresholds["slowdown"]; ok {
+		if t, ok := gpuThresh["slowdown"]; ok && validThreshold(t) {
 			r.High = t
 			r.HasHigh = true
 		}
-		if t, ok := thresholds["shutdown"]; ok {
+		if t, ok := gpuThresh["shutdown"]; ok && validThreshold(t) {
 			r.Crit = t
 			r.HasCrit = true
 		}
-
 		readings = append(readings, r)
+
+		// Memory temp is "N/A" on consumer cards without an HBM/GDDR6X sensor
+		if len(parts) < 4 {
+			continue
+		}
+		memTemp, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil {
+			continue
+		}
+		mem := Reading{
+			Chip:    chipName,
+			Adapter: name,
+			Label:   "GPU Mem",
+			Temp:    memTemp,
+			Source:  SourceNvidia,
+		}
+		if t, ok := gpuThresh["mem_max_operating"]; ok && validThreshold(t) {
+			mem.High = t
+			mem.HasHigh = true
+		}
+		readings = append(readings, mem)
 	}
 
 	return readings
@@ -67,27 +140,41 @@ func ReadNvidiaGPU() []Reading {
 
 var nvidiaTempValRe = regexp.MustCompile(`:\s*(\d+)\s*C`)
 
-func parseNvidiaThresholds() map[string]float64 {
-	out, err := exec.Command("nvidia-smi", "-q", "-d", "TEMPERATURE").Output()
-	if err != nil {
-		return nil
-	}
+// parseNvidiaThresholds parses `nvidia-smi -q -d TEMPERATURE` output, which
+// is sectioned by "GPU <bus-id>" headers, into one threshold map per GPU.
+func parseNvidiaThresholds(output string) []map[string]float64 {
+	var result []map[string]float64
+	var cur map[string]float64
 
-	result := make(map[string]float64)
-	for _, line := range strings.Split(string(out), "\n") {
+	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
 
-		if strings.HasPrefix(line, "GPU Shutdown Temp") {
+		// Section header is "GPU 00000000:01:00.0"; fields use " : "
+		if strings.HasPrefix(line, "GPU ") && !strings.Contains(line, " : ") {
+			cur = make(map[string]float64)
+			result = append(result, cur)
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "GPU Shutdown Temp"):
 			if v := extractNvidiaTemp(line); v > 0 {
-				result["shutdown"] = v
+				cur["shutdown"] = v
 			}
-		} else if strings.HasPrefix(line, "GPU Slowdown Temp") {
+		case strings.HasPrefix(line, "GPU Slowdown Temp"):
 			if v := extractNvidiaTemp(line); v > 0 {
-				result["slowdown"] = v
+				cur["slowdown"] = v
 			}
-		} else if strings.HasPrefix(line, "GPU Max Operating Temp") {
+		case strings.HasPrefix(line, "GPU Max Operating Temp"):
 			if v := extractNvidiaTemp(line); v > 0 {
-				result["max_operating"] = v
+				cur["max_operating"] = v
+			}
+		case strings.HasPrefix(line, "Memory Max Operating Temp"):
+			if v := extractNvidiaTemp(line); v > 0 {
+				cur["mem_max_operating"] = v
 			}
 		}
 	}
@@ -106,12 +193,113 @@ func extractNvidiaTemp(line string) float64 {
 	return v
 }
 
+// ReadAMDGPU reads AMD GPU temperatures, power draw and fan speed via
+// rocm-smi. Returns nil if rocm-smi is not available or fails.
+func ReadAMDGPU() []Reading {
+	readings, _ := readAMDGPU(context.Background())
+	return readings
+}
+
+func readAMDGPU(ctx context.Context) ([]Reading, error) {
+	path, err := lookPath("rocm-smi")
+	if err != nil || path == "" {
+		return nil, nil
+	}
+
+	out, err := runCommand(ctx, "rocm-smi", "--showtemp", "--showpower", "--showfan", "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parseRocmSMI(out), nil
+}
+
+// parseRocmSMI parses `rocm-smi --json` output, which maps "cardN" to a
+// flat object of human-readable keys with string values.
+func parseRocmSMI(out []byte) []Reading {
+	var data map[string]map[string]string
+	if err := json.Unmarshal(out, &data); err != nil {
+		return nil
+	}
+
+	cards := make([]string, 0, len(data))
+	for k := range data {
+		if strings.HasPrefix(k, "card") {
+			cards = append(cards, k)
+		}
+	}
+	sort.Strings(cards)
+
+	var readings []Reading
+	for _, card := range cards {
+		fields := data[card]
+		chipName := "amdgpu-rocm-" + strings.TrimPrefix(card, "card")
+		adapter := "ROCm"
+		if name, ok := fields["Card series"]; ok {
+			adapter = name
+		}
+
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var fanRPM, fanPct *Reading
+		for _, k := range keys {
+			v, err := strconv.ParseFloat(strings.TrimSpace(fields[k]), 64)
+			if err != nil {
+				continue
+			}
+			lower := strings.ToLower(k)
+			r := Reading{Chip: chipName, Adapter: adapter, Temp: v, Source: SourceROCm}
+
+			switch {
+			case strings.HasPrefix(lower, "temperature") && strings.Contains(lower, "edge"):
+				r.Label = "edge"
+			case strings.HasPrefix(lower, "temperature") && strings.Contains(lower, "junction"):
+				r.Label = "junction"
+			case strings.HasPrefix(lower, "temperature") && strings.Contains(lower, "mem"):
+				r.Label = "mem"
+			case strings.Contains(lower, "power") && strings.HasSuffix(lower, "(w)"):
+				r.Label = "Power"
+				r.Unit = "W"
+			case lower == "fan rpm":
+				r.Label = "Fan"
+				r.Unit = "RPM"
+				fanRPM = &r
+				continue
+			case strings.HasPrefix(lower, "fan speed") && strings.HasSuffix(lower, "(%)"):
+				r.Label = "Fan"
+				r.Unit = "%"
+				fanPct = &r
+				continue
+			default:
+				continue
+			}
+			readings = append(readings, r)
+		}
+
+		// Prefer RPM when the card reports it; percent is the fallback
+		if fanRPM != nil {
+			readings = append(readings, *fanRPM)
+		} else if fanPct != nil {
+			readings = append(readings, *fanPct)
+		}
+	}
+
+	return readings
+}
+
 // ReadDriveTemps reads HDD/SSD temperatures via the drivetemp kernel module
 // (sysfs hwmon) or falls back to smartctl for SATA drives not exposed via hwmon.
 func ReadDriveTemps() []Reading {
+	return readDriveTemps(context.Background())
+}
+
+func readDriveTemps(ctx context.Context) []Reading {
 	var readings []Reading
 	readings = append(readings, readDrivetempHwmon()...)
-	readings = append(readings, readSmartctlDrives()...)
+	readings = append(readings, readSmartctlDrives(ctx)...)
 	return readings
 }
 
@@ -146,27 +334,45 @@ func readDrivetempHwmon() []Reading {
 			Adapter: "SATA drive",
 			Label:   "Drive Temp",
 			Temp:    temp,
+			Source:  SourceDrivetemp,
 		})
 	}
 	return readings
 }
 
-func readSmartctlDrives() []Reading {
-	path, err := exec.LookPath("smartctl")
+func readSmartctlDrives(ctx context.Context) []Reading {
+	readings, _ := scanSmartctl(ctx)
+	return readings
+}
+
+// smartPermissionHint is shown by doctor when smartctl could not open a
+// drive. smartctl is never run through sudo: a misconfigured sudoers can
+// prompt or hang, and spawning sudo per drive per poll is a lot of churn.
+const smartPermissionHint = "grant smartctl raw disk access " +
+	"(sudo setcap cap_sys_rawio,cap_sys_admin+ep $(command -v smartctl)) or run as root"
+
+// scanSmartctl reads SATA and NVMe drives via unprivileged smartctl. It
+// also returns how many drives could not be opened for lack of permission.
+func scanSmartctl(ctx context.Context) ([]Reading, int) {
+	path, err := lookPath("smartctl")
 	if err != nil || path == "" {
-		return nil
+		return nil, 0
 	}
 
-	drives, _ := filepath.Glob("/dev/sd?")
+	drives, _ := listDevices("/dev/sd?")
 	var readings []Reading
+	denied := 0
 
 	for _, dev := range drives {
-		out, err := exec.Command("sudo", "-n", "smartctl", "-A", dev).Output()
+		if ctx.Err() != nil {
+			return readings, denied
+		}
+		out, err := runCommand(ctx, "smartctl", "-A", dev)
 		if err != nil {
-			out, err = exec.Command("smartctl", "-A", dev).Output()
-			if err != nil {
-				continue
+			if permissionDenied(out) {
+				denied++
 			}
+			continue
 		}
 
 		temp, ok := parseSmartTemp(string(out))
@@ -174,7 +380,7 @@ func readSmartctlDrives() []Reading {
 			continue
 		}
 
-		model := getSmartModel(dev)
+		model := getSmartModel(ctx, dev)
 		if model == "" {
 			model = "SATA drive"
 		}
@@ -185,14 +391,87 @@ func readSmartctlDrives() []Reading {
 			Adapter: model,
 			Label:   "Drive Temp",
 			Temp:    temp,
-			High:    55,
-			HasHigh: true,
-			Crit:    60,
-			HasCrit: true,
+			Source:  SourceSmartctl,
 		})
 	}
 
-	return readings
+	nvme, nvmeDenied := readSmartctlNVMe(ctx)
+	return append(readings, nvme...), denied + nvmeDenied
+}
+
+// permissionDenied reports whether smartctl failed to open the device
+// because of missing privileges.
+func permissionDenied(out []byte) bool {
+	return strings.Contains(string(out), "Permission denied") ||
+		strings.Contains(string(out), "Operation not permitted")
+}
+
+// readSmartctlNVMe reads NVMe namespaces that have no nvme hwmon (and so
+// are missing from lm-sensors), typically drives behind USB enclosures.
+func readSmartctlNVMe(ctx context.Context) ([]Reading, int) {
+	namespaces, _ := listDevices("/dev/nvme?n?")
+	var readings []Reading
+	denied := 0
+
+	for _, dev := range namespaces {
+		if ctx.Err() != nil {
+			return readings, denied
+		}
+		devName := filepath.Base(dev)
+		if hasNVMeHwmon(devName) {
+			continue
+		}
+
+		// smartctl -j exits non-zero for drive warnings as well as
+		// failures, so judge by the JSON rather than the exit status.
+		out, _ := runCommand(ctx, "smartctl", "-j", "-A", "-i", dev)
+		temp, model, ok := parseSmartJSON(out)
+		if !ok {
+			if permissionDenied(out) {
+				denied++
+			}
+			continue
+		}
+		if model == "" {
+			model = "NVMe drive"
+		}
+
+		readings = append(readings, Reading{
+			Chip:    "smart-" + devName,
+			Adapter: model,
+			Label:   "Drive Temp",
+			Temp:    temp,
+			Source:  SourceSmartctl,
+		})
+	}
+
+	return readings, denied
+}
+
+// hasNVMeHwmon reports whether the controller behind an NVMe namespace
+// exposes a hwmon device, in which case lm-sensors already reports it.
+func hasNVMeHwmon(namespace string) bool {
+	matches, _ := filepath.Glob(filepath.Join("/sys/block", namespace, "device", "hwmon*"))
+	return len(matches) > 0
+}
+
+// parseSmartJSON extracts temperature.current and the model name from
+// `smartctl -j` output. ok is false when the drive could not be opened
+// (e.g. missing permissions) or reported no temperature.
+func parseSmartJSON(out []byte) (temp float64, model string, ok bool) {
+	var data struct {
+		ModelName   string `json:"model_name"`
+		Temperature *struct {
+			Current *float64 `json:"current"`
+		} `json:"temperature"`
+	}
+	if err := json.Unmarshal(out, &data); err != nil {
+		return 0, "", false
+	}
+	if data.Temperature == nil || data.Temperature.Current == nil {
+		return 0, "", false
+	}
+	return *data.Temperature.Current, data.ModelName, true
 }
 
 var smartTempRe = regexp.MustCompile(`(?:194\s+Temperature_Celsius|190\s+Airflow_Temperature_Cel)\s+\S+\s+(\d+)`)
@@ -223,15 +502,37 @@ func parseSmartTemp(output string) (float64, bool) {
 	return 0, false
 }
 
-func getSmartModel(dev string) string {
-	out, err := exec.Command("sudo", "-n", "smartctl", "-i", dev).Output()
+// smartModels caches drive models by device path for the process
+// lifetime, so polling only runs `smartctl -A`.
+var (
+	smartModelsMu sync.Mutex
+	smartModels   = make(map[string]string)
+)
+
+// getSmartModel returns the drive model, running `smartctl -i` only the
+// first time a device is seen. Failed lookups are retried next poll.
+func getSmartModel(ctx context.Context, dev string) string {
+	smartModelsMu.Lock()
+	model, ok := smartModels[dev]
+	smartModelsMu.Unlock()
+	if ok {
+		return model
+	}
+
+	out, err := runCommand(ctx, "smartctl", "-i", dev)
 	if err != nil {
-		out, err = exec.Command("smartctl", "-i", dev).Output()
-		if err != nil {
-			return ""
-		}
+		return ""
 	}
-	for _, line := range strings.Split(string(out), "\n") {
+	model = parseSmartModel(string(out))
+
+	smartModelsMu.Lock()
+	smartModels[dev] = model
+	smartModelsMu.Unlock()
+	return model
+}
+
+func parseSmartModel(output string) string {
+	for _, line := range strings.Split(output, "\n") {
 		if strings.HasPrefix(line, "Device Model:") {
 			return strings.TrimSpace(strings.TrimPrefix(line, "Device Model:"))
 		}
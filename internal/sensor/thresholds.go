@@ -0,0 +1,58 @@
+package sensor
+
+// Thresholds are fallback high/crit limits for one component. A zero
+// limit is left unset.
+type Thresholds struct {
+	High float64
+	Crit float64
+}
+
+// componentDefaults maps FriendlyName components to the limits applied to
+// readings whose hardware reports none, so they still get colored bands.
+var componentDefaults = DefaultComponentThresholds()
+
+// DefaultComponentThresholds returns the built-in per-component limits.
+// SATA drives rarely expose thresholds and are rated to about 60°C; WiFi
+// cards start throttling around 80°C; DDR5 DIMMs are rated to 85°C and
+// throttle refresh above it.
+func DefaultComponentThresholds() map[string]Thresholds {
+	return map[string]Thresholds{
+		"HDD/SSD":       {High: 55, Crit: 60},
+		"WiFi":          {High: 80},
+		"Memory (DIMM)": {High: 85, Crit: 95},
+	}
+}
+
+// SetComponentThresholds overlays per-component limits on the built-in
+// table. An entry replaces the built-in one for that component, so an
+// empty entry turns the fallback off.
+func SetComponentThresholds(overrides map[string]Thresholds) {
+	table := DefaultComponentThresholds()
+	for name, t := range overrides {
+		table[name] = t
+	}
+	componentDefaults = table
+}
+
+// applyComponentThresholds fills in the high and crit limits that the
+// hardware did not report from the component table. Limits the hardware
+// does report are never overridden, and a default that would end up on
+// the wrong side of one (a crit below the hardware high) is left out.
+func applyComponentThresholds(readings []Reading) {
+	for i := range readings {
+		r := &readings[i]
+		if r.Unit != "" {
+			continue
+		}
+		t, ok := componentDefaults[FriendlyName(r.Chip)]
+		if !ok {
+			continue
+		}
+		if !r.HasHigh && t.High > 0 && (!r.HasCrit || t.High < r.Crit) {
+			r.High, r.HasHigh = t.High, true
+		}
+		if !r.HasCrit && t.Crit > 0 && (!r.HasHigh || t.Crit > r.High) {
+			r.Crit, r.HasCrit = t.Crit, true
+		}
+	}
+}
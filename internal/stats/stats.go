@@ -0,0 +1,190 @@
+// Package stats computes per-sensor summary statistics over stored
+// readings for batch reports such as `sensors stats` and `sensors compare`.
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/luki/sensors/internal/store"
+)
+
+// gapFactor is how many median sample intervals a hole must span to count
+// as a sampling gap (e.g. the recorder was stopped or the machine slept).
+const gapFactor = 3
+
+// Summary describes one sensor over a set of readings.
+type Summary struct {
+	Key        string        `json:"key"`
+	Chip       string        `json:"chip"`
+	Label      string        `json:"label"`
+	Unit       string        `json:"unit,omitempty"`
+	Samples    int           `json:"samples"`
+	Min        float64       `json:"min"`
+	Avg        float64       `json:"avg"`
+	Max        float64       `json:"max"`
+	P95        float64       `json:"p95"`
+	High       float64       `json:"high,omitempty"`
+	Crit       float64       `json:"crit,omitempty"`
+	AboveHigh  time.Duration `json:"-"`
+	AboveCrit  time.Duration `json:"-"`
+	Gaps       int           `json:"gaps"`
+	LongestGap time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes durations as seconds.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	type plain Summary
+	return json.Marshal(struct {
+		plain
+		AboveHigh  float64 `json:"above_high_s"`
+		AboveCrit  float64 `json:"above_crit_s"`
+		LongestGap float64 `json:"longest_gap_s"`
+	}{plain(s), s.AboveHigh.Seconds(), s.AboveCrit.Seconds(), s.LongestGap.Seconds()})
+}
+
+// Summarize groups readings by sensor key and returns one summary per
+// sensor, sorted by key. Readings are expected in time order, as LoadDay
+// returns them.
+func Summarize(readings []store.StoredReading) []Summary {
+	byKey := make(map[string][]store.StoredReading)
+	var keys []string
+	for _, r := range readings {
+		k := r.Key()
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], r)
+	}
+	sort.Strings(keys)
+
+	out := make([]Summary, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, summarize(k, byKey[k]))
+	}
+	return out
+}
+
+func summarize(key string, rs []store.StoredReading) Summary {
+	last := rs[len(rs)-1]
+	s := Summary{
+		Key:     key,
+		Chip:    last.Chip,
+		Label:   last.Label,
+		Unit:    last.Unit,
+		Samples: len(rs),
+		Min:     math.MaxFloat64,
+		Max:     -math.MaxFloat64,
+		High:    last.High,
+		Crit:    last.Crit,
+	}
+
+	vals := make([]float64, len(rs))
+	sum := 0.0
+	for i, r := range rs {
+		vals[i] = r.Temp
+		sum += r.Temp
+		s.Min = math.Min(s.Min, r.Temp)
+		s.Max = math.Max(s.Max, r.Temp)
+	}
+	s.Avg = sum / float64(len(rs))
+	s.P95 = Percentile(vals, 95)
+
+	times := make([]time.Time, len(rs))
+	for i, r := range rs {
+		times[i] = r.Time
+	}
+	step := store.MedianStep(times)
+	for i, r := range rs {
+		// Each sample stands for the time until the next one, but never
+		// across a gap and never longer than one typical interval
+		dt := step
+		if i+1 < len(rs) {
+			dt = rs[i+1].Time.Sub(r.Time)
+			if dt > gapFactor*step {
+				s.Gaps++
+				if dt > s.LongestGap {
+					s.LongestGap = dt
+				}
+				dt = step
+			}
+		}
+		if s.High > 0 && r.Temp >= s.High {
+			s.AboveHigh += dt
+		}
+		if s.Crit > 0 && r.Temp >= s.Crit {
+			s.AboveCrit += dt
+		}
+	}
+	return s
+}
+
+// Percentile returns the p-th percentile (0-100) of vals using the
+// nearest-rank method. vals is not modified.
+func Percentile(vals []float64, p float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(vals))
+	copy(sorted, vals)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// Delta compares one sensor across two sets of readings. Before or After
+// is nil when the sensor only appears on one side.
+type Delta struct {
+	Key      string   `json:"key"`
+	Unit     string   `json:"unit,omitempty"`
+	Before   *Summary `json:"before,omitempty"`
+	After    *Summary `json:"after,omitempty"`
+	AvgDelta float64  `json:"avg_delta"`
+	MaxDelta float64  `json:"max_delta"`
+	Hotter   bool     `json:"hotter"`
+}
+
+// Compare matches summaries by key and returns per-sensor changes in avg
+// and peak, sorted by key. A temperature sensor is flagged Hotter when its
+// average rose by at least threshold degrees.
+func Compare(before, after []Summary, threshold float64) []Delta {
+	byKey := make(map[string]*Delta)
+	var keys []string
+	get := func(s Summary) *Delta {
+		d, ok := byKey[s.Key]
+		if !ok {
+			d = &Delta{Key: s.Key, Unit: s.Unit}
+			byKey[s.Key] = d
+			keys = append(keys, s.Key)
+		}
+		return d
+	}
+	for i := range before {
+		get(before[i]).Before = &before[i]
+	}
+	for i := range after {
+		get(after[i]).After = &after[i]
+	}
+	sort.Strings(keys)
+
+	out := make([]Delta, 0, len(keys))
+	for _, k := range keys {
+		d := byKey[k]
+		if d.Before != nil && d.After != nil {
+			d.AvgDelta = d.After.Avg - d.Before.Avg
+			d.MaxDelta = d.After.Max - d.Before.Max
+			d.Hotter = d.Unit == "" && d.AvgDelta >= threshold
+		}
+		out = append(out, *d)
+	}
+	return out
+}
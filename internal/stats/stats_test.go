@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luki/sensors/internal/store"
+)
+
+func TestPercentile(t *testing.T) {
+	vals := []float64{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	if got := Percentile(vals, 95); got != 10 {
+		t.Errorf("p95: got %f, want 10", got)
+	}
+	if got := Percentile(vals, 50); got != 5 {
+		t.Errorf("p50: got %f, want 5", got)
+	}
+	if vals[0] != 5 {
+		t.Error("Percentile modified its input")
+	}
+	if got := Percentile(nil, 95); got != 0 {
+		t.Errorf("empty: got %f, want 0", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var rs []store.StoredReading
+	add := func(sec int, chip, label string, temp float64) {
+		rs = append(rs, store.StoredReading{
+			Time: base.Add(time.Duration(sec) * time.Second), Chip: chip, Label: label,
+			Temp: temp, High: 80, Crit: 90,
+		})
+	}
+
+	// 10 samples at 1s, a 5 minute gap, then 10 more
+	for i := 0; i < 10; i++ {
+		add(i, "coretemp-isa-0000", "Core 0", 70)
+		add(i, "nvme-pci-0300", "Composite", 40)
+	}
+	for i := 0; i < 10; i++ {
+		temp := 85.0
+		if i >= 7 {
+			temp = 95
+		}
+		add(310+i, "coretemp-isa-0000", "Core 0", temp)
+	}
+
+	sums := Summarize(rs)
+	if len(sums) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(sums))
+	}
+	core := sums[0]
+	if core.Key != "coretemp-isa-0000/Core 0" {
+		t.Fatalf("summaries not sorted by key: %s first", core.Key)
+	}
+	if core.Samples != 20 || core.Min != 70 || core.Max != 95 {
+		t.Errorf("unexpected core summary: %+v", core)
+	}
+	if core.Avg != (10*70+7*85+3*95)/20.0 {
+		t.Errorf("Avg: got %f", core.Avg)
+	}
+	if core.P95 != 95 {
+		t.Errorf("P95: got %f, want 95", core.P95)
+	}
+	if core.Gaps != 1 || core.LongestGap != 301*time.Second {
+		t.Errorf("gaps: got %d (longest %v), want 1 (5m1s)", core.Gaps, core.LongestGap)
+	}
+	if core.AboveHigh != 10*time.Second {
+		t.Errorf("AboveHigh: got %v, want 10s", core.AboveHigh)
+	}
+	if core.AboveCrit != 3*time.Second {
+		t.Errorf("AboveCrit: got %v, want 3s", core.AboveCrit)
+	}
+
+	nvme := sums[1]
+	if nvme.Gaps != 0 || nvme.AboveHigh != 0 {
+		t.Errorf("unexpected nvme summary: %+v", nvme)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := []Summary{
+		{Key: "coretemp-isa-0000/Core 0", Avg: 60, Max: 80},
+		{Key: "nvme-pci-0300/Composite", Avg: 40, Max: 45},
+		{Key: "amdgpu-rocm-0/Power", Unit: "W", Avg: 100, Max: 150},
+		{Key: "old/Gone", Avg: 30, Max: 30},
+	}
+	after := []Summary{
+		{Key: "amdgpu-rocm-0/Power", Unit: "W", Avg: 180, Max: 250},
+		{Key: "coretemp-isa-0000/Core 0", Avg: 52, Max: 71},
+		{Key: "new/Added", Avg: 35, Max: 36},
+		{Key: "nvme-pci-0300/Composite", Avg: 46, Max: 50},
+	}
+
+	deltas := Compare(before, after, 3)
+	if len(deltas) != 5 {
+		t.Fatalf("expected 5 deltas, got %d", len(deltas))
+	}
+	byKey := make(map[string]Delta)
+	for _, d := range deltas {
+		byKey[d.Key] = d
+	}
+
+	if d := byKey["coretemp-isa-0000/Core 0"]; d.AvgDelta != -8 || d.MaxDelta != -9 || d.Hotter {
+		t.Errorf("cooler sensor: %+v", d)
+	}
+	if d := byKey["nvme-pci-0300/Composite"]; d.AvgDelta != 6 || !d.Hotter {
+		t.Errorf("hotter sensor not flagged: %+v", d)
+	}
+	if d := byKey["amdgpu-rocm-0/Power"]; d.Hotter {
+		t.Error("non-temperature sensor flagged as hotter")
+	}
+	if d := byKey["old/Gone"]; d.Before == nil || d.After != nil {
+		t.Errorf("removed sensor: %+v", d)
+	}
+	if d := byKey["new/Added"]; d.Before != nil || d.After == nil {
+		t.Errorf("added sensor: %+v", d)
+	}
+	if deltas[0].Key != "amdgpu-rocm-0/Power" {
+		t.Errorf("deltas not sorted: %s first", deltas[0].Key)
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// eventsPrefix names the per-day annotation sidecar files, stored next to
+// the readings as events-YYYY-MM-DD.csv with the format:
+//
+//	time,label
+const eventsPrefix = "events-"
+
+// Event is a timestamped annotation such as the start of a stress run.
+type Event struct {
+	Time  time.Time
+	Label string
+}
+
+// AppendEvent appends an annotation to the event log for t's day.
+func AppendEvent(label string, t time.Time) error {
+	t = t.In(location)
+	dir := DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, eventsPrefix+t.Format(fileLayout)+".csv")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		w.Write([]string{"time", "label"})
+	}
+	w.Write([]string{t.Format(timeLayout), label})
+	w.Flush()
+	return w.Error()
+}
+
+// LoadEvents reads a day's annotations sorted by time. A missing event
+// file yields no events and no error.
+func LoadEvents(day string) ([]Event, error) {
+	return loadEventsFile(filepath.Join(DataDir(), eventsPrefix+day+".csv"))
+}
+
+func loadEventsFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for i, row := range records {
+		if i == 0 && len(row) > 0 && row[0] == "time" {
+			continue
+		}
+		if len(row) < 2 {
+			continue
+		}
+		t, err := parseTime(row[0])
+		if err != nil {
+			continue
+		}
+		events = append(events, Event{Time: t, Label: row[1]})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
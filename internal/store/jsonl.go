@@ -0,0 +1,118 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// jsonlExt names JSON Lines files, written one object per reading:
+//
+//	{"time":"2026-02-21T14:00:00","chip":"coretemp-isa-0000","adapter":"ISA adapter","label":"Core 0","temp":52,...}
+//
+// Unlike CSV columns, new fields can be added without a schema change.
+const jsonlExt = ".jsonl"
+
+// Format is the encoding of the daily files written by New.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// format is the encoding New writes, see SetFormat.
+var format = FormatCSV
+
+// SetFormat selects the encoding of day files written by stores created by
+// New: "csv" (the default) or "jsonl". LoadDay reads both, so a data
+// directory may mix them.
+func SetFormat(name string) error {
+	switch f := Format(name); f {
+	case FormatCSV, FormatJSONL:
+		format = f
+		return nil
+	}
+	return fmt.Errorf("unknown store format %q (want csv or jsonl)", name)
+}
+
+func isJSONL(path string) bool {
+	return filepath.Ext(path) == jsonlExt
+}
+
+// jsonRecord is one line of a JSON Lines file: every sensor.Reading field
+// plus the wall-clock time, in the same layout as the CSV time column.
+type jsonRecord struct {
+	Time    string        `json:"time"`
+	Chip    string        `json:"chip"`
+	Adapter string        `json:"adapter,omitempty"`
+	Label   string        `json:"label"`
+	Temp    float64       `json:"temp"`
+	High    float64       `json:"high,omitempty"`
+	Crit    float64       `json:"crit,omitempty"`
+	HasHigh bool          `json:"has_high,omitempty"`
+	HasCrit bool          `json:"has_crit,omitempty"`
+	Unit    string        `json:"unit,omitempty"`
+	Source  sensor.Source `json:"source,omitempty"`
+}
+
+// writeJSONL appends a batch as one write, so a reader never sees half a
+// batch's lines.
+func (d *DiskStore) writeJSONL(ts string, readings []sensor.Reading) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range readings {
+		if err := enc.Encode(jsonRecord{
+			Time:    ts,
+			Chip:    r.Chip,
+			Adapter: r.Adapter,
+			Label:   r.Label,
+			Temp:    r.Temp,
+			High:    r.High,
+			Crit:    r.Crit,
+			HasHigh: r.HasHigh,
+			HasCrit: r.HasCrit,
+			Unit:    r.Unit,
+			Source:  r.Source,
+		}); err != nil {
+			return err
+		}
+	}
+	_, err := d.current.Write(buf.Bytes())
+	return err
+}
+
+// loadJSONL reads a JSON Lines file. Lines that do not parse, such as one
+// cut short by a crash mid-write, are skipped like malformed CSV rows.
+func loadJSONL(r io.Reader) ([]StoredReading, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var readings []StoredReading
+	for sc.Scan() {
+		var rec jsonRecord
+		if json.Unmarshal(sc.Bytes(), &rec) != nil {
+			continue
+		}
+		t, err := parseTime(rec.Time)
+		if err != nil {
+			continue
+		}
+		readings = append(readings, StoredReading{
+			Time:    t,
+			Chip:    rec.Chip,
+			Adapter: rec.Adapter,
+			Label:   rec.Label,
+			Temp:    rec.Temp,
+			High:    rec.High,
+			Crit:    rec.Crit,
+			Unit:    rec.Unit,
+			Source:  rec.Source,
+		})
+	}
+	return readings, sc.Err()
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// peaksFile holds the all-time peak per sensor key, kept next to the
+// readings so it survives restarts.
+const peaksFile = "peaks.json"
+
+// Peak is the hottest temperature a sensor has reported and when.
+type Peak struct {
+	Temp float64   `json:"temp"`
+	Time time.Time `json:"time"`
+}
+
+// Peaks maps sensor keys to their all-time peak.
+type Peaks map[string]Peak
+
+// Observe records temp as key's peak if it is hotter than the stored one,
+// reporting whether it was.
+func (p Peaks) Observe(key string, temp float64, t time.Time) bool {
+	if old, ok := p[key]; ok && old.Temp >= temp {
+		return false
+	}
+	p[key] = Peak{Temp: temp, Time: t}
+	return true
+}
+
+// LoadPeaks reads the all-time peaks. A missing file yields empty peaks
+// and no error. A corrupt file is moved aside to peaks.json.corrupt so the
+// next save starts fresh without losing it, and also yields empty peaks
+// along with the error.
+func LoadPeaks() (Peaks, error) {
+	path := filepath.Join(DataDir(), peaksFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Peaks{}, nil
+		}
+		return Peaks{}, err
+	}
+	var peaks Peaks
+	if err := json.Unmarshal(data, &peaks); err != nil {
+		os.Rename(path, path+".corrupt")
+		return Peaks{}, fmt.Errorf("%s: %w (moved to %s.corrupt)", path, err, peaksFile)
+	}
+	if peaks == nil {
+		peaks = Peaks{}
+	}
+	return peaks, nil
+}
+
+// SavePeaks writes the all-time peaks, replacing the file atomically so a
+// crash mid-write never leaves it truncated.
+func SavePeaks(peaks Peaks) error {
+	dir := DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(peaks, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, peaksFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSize parses a size such as "500M", "2G" or "750000" into bytes.
+// K, M and G are powers of 1024 and may be followed by "B" or "iB". A
+// size under one byte is rejected: as a cap it would delete every day
+// but the current one.
+func ParseSize(s string) (int64, error) {
+	num := strings.TrimSpace(s)
+	num = strings.TrimSuffix(strings.TrimSuffix(num, "B"), "i")
+	mult := int64(1)
+	if n := len(num); n > 0 {
+		switch strings.ToUpper(num[n-1:]) {
+		case "K":
+			mult = 1 << 10
+		case "M":
+			mult = 1 << 20
+		case "G":
+			mult = 1 << 30
+		}
+		if mult > 1 {
+			num = num[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %q, want e.g. 500M or 2G", s)
+	}
+	n := int64(v * float64(mult))
+	if n < 1 {
+		return 0, fmt.Errorf("size %q must be at least one byte", s)
+	}
+	return n, nil
+}
+
+// dayFiles groups the data directory's files by the day they belong to:
+// readings in CSV or JSON Lines and the day's event log. It also returns
+// the size of every file in the directory, day files or not.
+func dayFiles(dir string) (map[string][]string, map[string]int64, int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	files := make(map[string][]string)
+	sizes := make(map[string]int64)
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+		name := e.Name()
+		day, ok := strings.CutSuffix(strings.TrimPrefix(name, eventsPrefix), ".csv")
+		if !ok {
+			day, ok = strings.CutSuffix(name, jsonlExt)
+		}
+		if _, err := time.Parse(fileLayout, day); !ok || err != nil {
+			continue
+		}
+		files[day] = append(files[day], name)
+		sizes[day] += info.Size()
+	}
+	return files, sizes, total, nil
+}
+
+// PlanSizeCap returns the days, oldest first, that EnforceSizeCap would
+// delete to bring the data directory to at most maxBytes, and the
+// directory's current size. The newest day is never picked since it is
+// the one being recorded, so the result may still be over the cap.
+func PlanSizeCap(maxBytes int64) ([]string, int64, error) {
+	victims, _, total, err := planSizeCap(DataDir(), maxBytes)
+	return victims, total, err
+}
+
+func planSizeCap(dir string, maxBytes int64) ([]string, map[string][]string, int64, error) {
+	files, sizes, total, err := dayFiles(dir)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	days := make([]string, 0, len(files))
+	for day := range files {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	var victims []string
+	size := total
+	for i := 0; i < len(days)-1 && size > maxBytes; i++ {
+		victims = append(victims, days[i])
+		size -= sizes[days[i]]
+	}
+	return victims, files, total, nil
+}
+
+// EnforceSizeCap deletes whole days, oldest first, until the data
+// directory holds at most maxBytes, and returns the days it removed. On
+// a Raspberry Pi or other small disk this keeps a long-running recorder
+// from filling it.
+func EnforceSizeCap(maxBytes int64) ([]string, error) {
+	dir := DataDir()
+	victims, files, _, err := planSizeCap(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, day := range victims {
+		for _, name := range files[day] {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return removed, err
+			}
+		}
+		removed = append(removed, day)
+	}
+	return removed, nil
+}
@@ -1,5 +1,12 @@
-// Package store handles persistent CSV storage of temperature readings
-// with daily file rotation. Data is stored in ~/.sensors-data/.
+// Package store handles persistent CSV (or JSON Lines, see SetFormat)
+// storage of temperature readings with daily file rotation. Data is stored
+// in ~/.sensors-data/.
+//
+// Timestamps are written as wall-clock time in the store's location (see
+// SetLocation), which also decides where one day file ends and the next
+// begins, with their UTC offset so the hour repeated when DST ends loads
+// back as two instants. Rows from before offsets were written are read
+// as wall-clock time in the location.
 package store
 
 import (
@@ -7,6 +14,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,81 +23,250 @@ import (
 )
 
 const (
-	dirName    = ".sensors-data"
-	timeLayout = "2006-01-02T15:04:05"
-	fileLayout = "2006-01-02"
+	dirName      = ".sensors-data"
+	timeLayout   = "2006-01-02T15:04:05Z07:00"
+	milliLayout  = "2006-01-02T15:04:05.000Z07:00" // SetMillis
+	legacyLayout = "2006-01-02T15:04:05"           // no offset, fractions parse too
+	fileLayout   = "2006-01-02"
 )
 
+// columns is the current CSV schema. The header row names the columns, so
+// the loader maps them by name and new optional columns can be appended
+// without breaking files written with an older schema.
+var columns = []string{"time", "chip", "label", "temp", "high", "crit", "unit", "source", "adapter"}
+
+// legacyColumns is the original 6-column schema, assumed for files
+// without a header row.
+var legacyColumns = []string{"time", "chip", "label", "temp", "high", "crit"}
+
 // DiskStore handles persistent CSV storage of temperature readings.
 // Files are stored as ~/.sensors-data/YYYY-MM-DD.csv with the format:
 //
-//	timestamp,chip,label,temp,high,crit
+//	time,chip,label,temp,high,crit,unit,source,adapter
+//
+// Appending to a file created with an older schema keeps that file's
+// columns so every row in a file has the same shape.
 type DiskStore struct {
 	dir     string
+	path    string // fixed output file; empty means daily rotation in dir
+	layout  string // timestamp layout; empty means timeLayout
+	jsonl   bool   // write JSON Lines instead of CSV
 	current *os.File
 	writer  *csv.Writer
 	curDate string
+	cols    []string
 }
 
 // StoredReading is a single row from a CSV log file.
 type StoredReading struct {
-	Time  time.Time
-	Chip  string
-	Label string
-	Temp  float64
-	High  float64
-	Crit  float64
+	Time    time.Time
+	Chip    string
+	Adapter string // "" in CSV files written before the adapter column
+	Label   string
+	Temp    float64
+	High    float64
+	Crit    float64
+	Unit    string        // "" for temperature
+	Source  sensor.Source // "" in files written before the source column
+}
+
+// Key returns the same sensor identifier as sensor.Reading.Key.
+func (r StoredReading) Key() string {
+	return r.Chip + "/" + r.Label
+}
+
+// millis makes New write millisecond timestamps, see SetMillis.
+var millis bool
+
+// SetMillis makes stores created by New write timestamps with
+// milliseconds, for recording faster than once a second. LoadFile reads
+// either precision, so files may mix both.
+func SetMillis(on bool) {
+	millis = on
+}
+
+// parseTime reads a stored timestamp, with a UTC offset or, in files
+// written before offsets were added, as wall-clock time in location.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(location), nil
+	}
+	return time.ParseInLocation(legacyLayout, s, location)
+}
+
+// dataDirOverride replaces ~/.sensors-data when set via SetDataDir.
+var dataDirOverride string
+
+// SetDataDir overrides the data directory used by New, ListDays, LoadDay
+// and DataDir. An empty dir restores the default.
+func SetDataDir(dir string) {
+	dataDirOverride = dir
+}
+
+// location is the zone day files are named and timestamps written in.
+var location = time.Local
+
+// SetLocation sets the zone used to name day files and to write and parse
+// timestamps. It must stay the same between writing and reading a file: a
+// machine that moves between zones should pin one, e.g. UTC. A nil loc
+// restores the system zone.
+func SetLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.Local
+	}
+	location = loc
+}
+
+// Location returns the zone set by SetLocation.
+func Location() *time.Location {
+	return location
 }
 
 // New creates a new disk store, creating the data directory if needed.
 func New() (*DiskStore, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("cannot find home dir: %w", err)
+	dir := dataDirOverride
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot find home dir: %w", err)
+		}
+		dir = filepath.Join(home, dirName)
 	}
-	dir := filepath.Join(home, dirName)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("cannot create data dir: %w", err)
 	}
-	return &DiskStore{dir: dir}, nil
+	ds := &DiskStore{dir: dir, jsonl: format == FormatJSONL}
+	if millis {
+		ds.layout = milliLayout
+	}
+	return ds, nil
 }
 
-// Write appends a batch of sensor readings to today's CSV file.
+// NewFile creates a store that appends to a single file without daily
+// rotation, e.g. for `sensors record --out`. A .jsonl path is written as
+// JSON Lines, anything else as CSV. Timestamps keep milliseconds so
+// sub-second sampling survives the round trip.
+func NewFile(path string) (*DiskStore, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("cannot create output dir: %w", err)
+		}
+	}
+	return &DiskStore{path: path, layout: milliLayout, jsonl: isJSONL(path)}, nil
+}
+
+// Write appends a batch of sensor readings to today's file.
 func (d *DiskStore) Write(readings []sensor.Reading, t time.Time) error {
+	t = t.In(location)
 	dateStr := t.Format(fileLayout)
 
-	if d.curDate != dateStr || d.current == nil {
+	if d.current == nil || (d.path == "" && d.curDate != dateStr) {
 		d.Close()
-		path := filepath.Join(d.dir, dateStr+".csv")
-		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
+		path := d.path
+		if path == "" {
+			ext := ".csv"
+			if d.jsonl {
+				ext = jsonlExt
+			}
+			path = filepath.Join(d.dir, dateStr+ext)
+		}
+		if err := d.open(path); err != nil {
 			return err
 		}
-		d.current = f
-		d.writer = csv.NewWriter(f)
 		d.curDate = dateStr
-
-		info, _ := f.Stat()
-		if info.Size() == 0 {
-			d.writer.Write([]string{"time", "chip", "label", "temp", "high", "crit"})
-		}
 	}
 
-	ts := t.Format(timeLayout)
+	layout := d.layout
+	if layout == "" {
+		layout = timeLayout
+	}
+	ts := t.Format(layout)
+	if d.jsonl {
+		return d.writeJSONL(ts, readings)
+	}
 	for _, r := range readings {
-		d.writer.Write([]string{
-			ts,
-			r.Chip,
-			r.Label,
-			fmt.Sprintf("%.1f", r.Temp),
-			fmt.Sprintf("%.1f", r.High),
-			fmt.Sprintf("%.1f", r.Crit),
-		})
+		d.writer.Write(formatRow(d.cols, ts, r))
 	}
 	d.writer.Flush()
 	return d.writer.Error()
 }
 
+// open starts appending to path, writing a header if the file is new.
+func (d *DiskStore) open(path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	d.current = f
+	if d.jsonl {
+		return nil
+	}
+	d.writer = csv.NewWriter(f)
+
+	info, _ := f.Stat()
+	if info.Size() == 0 {
+		d.cols = columns
+		d.writer.Write(d.cols)
+	} else {
+		d.cols = readHeader(path)
+	}
+	return nil
+}
+
+// formatRow lays out a reading in the given column order.
+func formatRow(cols []string, ts string, r sensor.Reading) []string {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		switch c {
+		case "time":
+			row[i] = ts
+		case "chip":
+			row[i] = r.Chip
+		case "label":
+			row[i] = r.Label
+		case "temp":
+			row[i] = fmt.Sprintf("%.1f", r.Temp)
+		case "high":
+			row[i] = fmt.Sprintf("%.1f", r.High)
+		case "crit":
+			row[i] = fmt.Sprintf("%.1f", r.Crit)
+		case "unit":
+			row[i] = r.Unit
+		case "source":
+			row[i] = string(r.Source)
+		case "adapter":
+			row[i] = r.Adapter
+		}
+	}
+	return row
+}
+
+// readHeader returns the column names of an existing CSV file, falling
+// back to the legacy schema when the file has no header row.
+func readHeader(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return legacyColumns
+	}
+	defer f.Close()
+
+	header, err := csv.NewReader(f).Read()
+	if err != nil || !isHeader(header) {
+		return legacyColumns
+	}
+	return header
+}
+
+// isHeader reports whether a CSV row is a header, i.e. names a "time" column.
+func isHeader(row []string) bool {
+	for _, c := range row {
+		if c == "time" {
+			return true
+		}
+	}
+	return false
+}
+
 // Close flushes and closes the current file.
 func (d *DiskStore) Close() {
 	if d.writer != nil {
@@ -104,11 +281,7 @@ func (d *DiskStore) Close() {
 // ListDays returns available log dates (newest first).
 func ListDays(dir string) ([]string, error) {
 	if dir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
-		}
-		dir = filepath.Join(home, dirName)
+		dir = DataDir()
 	}
 
 	entries, err := os.ReadDir(dir)
@@ -116,72 +289,194 @@ func ListDays(dir string) ([]string, error) {
 		return nil, err
 	}
 
+	// A day switched between formats has both files; list it once
 	var days []string
+	seen := make(map[string]bool)
 	for i := len(entries) - 1; i >= 0; i-- {
 		name := entries[i].Name()
-		if strings.HasSuffix(name, ".csv") {
-			days = append(days, strings.TrimSuffix(name, ".csv"))
+		if strings.HasPrefix(name, eventsPrefix) {
+			continue
+		}
+		day, ok := strings.CutSuffix(name, ".csv")
+		if !ok {
+			day, ok = strings.CutSuffix(name, jsonlExt)
+		}
+		if ok && !seen[day] {
+			seen[day] = true
+			days = append(days, day)
 		}
 	}
 	return days, nil
 }
 
-// LoadDay reads all readings from a specific day's CSV file.
+// LoadDay reads all readings from a specific day's CSV and JSON Lines
+// files. Only a day with neither gives a not-exist error.
 func LoadDay(day string) ([]StoredReading, error) {
-	home, err := os.UserHomeDir()
+	var all []StoredReading
+	var missing error
+	files := 0
+	for _, ext := range []string{".csv", jsonlExt} {
+		readings, err := LoadFile(filepath.Join(DataDir(), day+ext))
+		if os.IsNotExist(err) {
+			missing = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files++
+		all = append(all, readings...)
+	}
+	switch files {
+	case 0:
+		return nil, missing
+	case 2:
+		sort.SliceStable(all, func(i, j int) bool { return all[i].Time.Before(all[j].Time) })
+	}
+	return all, nil
+}
+
+// LoadRecent returns the last n readings per sensor key from today's file,
+// oldest first. A missing file yields an empty map and no error.
+func LoadRecent(n int) (map[string][]StoredReading, error) {
+	readings, err := LoadDay(time.Now().In(location).Format(fileLayout))
 	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]StoredReading{}, nil
+		}
 		return nil, err
 	}
-	path := filepath.Join(home, dirName, day+".csv")
-	return LoadFile(path)
+	return recentByKey(readings, n), nil
+}
+
+func recentByKey(readings []StoredReading, n int) map[string][]StoredReading {
+	byKey := make(map[string][]StoredReading)
+	for _, r := range readings {
+		byKey[r.Key()] = append(byKey[r.Key()], r)
+	}
+	for k, rs := range byKey {
+		if len(rs) > n {
+			byKey[k] = rs[len(rs)-n:]
+		}
+	}
+	return byKey
 }
 
-// LoadFile reads all readings from a CSV file.
+// LoadFile reads all readings from a CSV file, or a JSON Lines file when
+// path ends in .jsonl.
 func LoadFile(path string) ([]StoredReading, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
+	if isJSONL(path) {
+		return loadJSONL(f)
+	}
 
 	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, err
 	}
 
+	cols := legacyColumns
+	if len(records) > 0 && isHeader(records[0]) {
+		cols = records[0]
+		records = records[1:]
+	}
+	idx := make(map[string]int, len(cols))
+	for i, c := range cols {
+		idx[c] = i
+	}
+	for _, required := range []string{"time", "chip", "label", "temp"} {
+		if _, ok := idx[required]; !ok {
+			return nil, fmt.Errorf("%s: missing %q column", path, required)
+		}
+	}
+
 	var readings []StoredReading
-	for i, row := range records {
-		if i == 0 && len(row) > 0 && row[0] == "time" {
-			continue
+	for _, row := range records {
+		get := func(name string) string {
+			if i, ok := idx[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
 		}
-		if len(row) < 6 {
+
+		t, err := parseTime(get("time"))
+		if err != nil {
 			continue
 		}
-
-		t, err := time.ParseInLocation(timeLayout, row[0], time.Local)
+		temp, err := strconv.ParseFloat(get("temp"), 64)
 		if err != nil {
 			continue
 		}
-		temp, _ := strconv.ParseFloat(row[3], 64)
-		high, _ := strconv.ParseFloat(row[4], 64)
-		crit, _ := strconv.ParseFloat(row[5], 64)
+		high, _ := strconv.ParseFloat(get("high"), 64)
+		crit, _ := strconv.ParseFloat(get("crit"), 64)
 
 		readings = append(readings, StoredReading{
-			Time:  t,
-			Chip:  row[1],
-			Label: row[2],
-			Temp:  temp,
-			High:  high,
-			Crit:  crit,
+			Time:    t,
+			Chip:    get("chip"),
+			Label:   get("label"),
+			Temp:    temp,
+			High:    high,
+			Crit:    crit,
+			Unit:    get("unit"),
+			Source:  sensor.Source(get("source")),
+			Adapter: get("adapter"),
 		})
 	}
 
 	return readings, nil
 }
 
+// MedianStep returns the median gap between consecutive times, the
+// dominant sampling interval of a recording, or one second when there is
+// no gap to go by. times must be sorted.
+func MedianStep(times []time.Time) time.Duration {
+	var gaps []time.Duration
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d > 0 {
+			gaps = append(gaps, d)
+		}
+	}
+	if len(gaps) == 0 {
+		return time.Second
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// Dedup collapses readings that share a sensor key and timestamp, to the
+// millisecond, keeping the last one written. This happens when two
+// processes (e.g. a recorder and an interactive monitor) append to the
+// same day file. Whole-second timestamps collapse per second.
+func Dedup(readings []StoredReading) []StoredReading {
+	type slot struct {
+		key string
+		ms  int64
+	}
+	index := make(map[slot]int, len(readings))
+	out := make([]StoredReading, 0, len(readings))
+	for _, r := range readings {
+		k := slot{r.Key(), r.Time.UnixMilli()}
+		if i, ok := index[k]; ok {
+			out[i] = r
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, r)
+	}
+	return out
+}
+
 // DataDir returns the path to the data directory.
 func DataDir() string {
+	if dataDirOverride != "" {
+		return dataDirOverride
+	}
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, dirName)
 }
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -16,7 +18,7 @@ func TestDiskStoreRoundTrip(t *testing.T) {
 	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
 	readings := []sensor.Reading{
 		{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 45.0, High: 101.0, Crit: 115.0, HasHigh: true, HasCrit: true},
-		{Chip: "nvme-pci-0300", Label: "Composite", Temp: 36.9, High: 81.8, Crit: 84.8, HasHigh: true, HasCrit: true},
+		{Chip: "nvme-pci-0300", Adapter: "Samsung SSD 980 PRO, 1TB", Label: "Composite", Temp: 36.9, High: 81.8, Crit: 84.8, HasHigh: true, HasCrit: true},
 	}
 
 	if err := ds.Write(readings, now); err != nil {
@@ -36,7 +38,487 @@ func TestDiskStoreRoundTrip(t *testing.T) {
 	if loaded[0].Chip != "coretemp-isa-0000" || loaded[0].Temp != 45.0 {
 		t.Errorf("first reading: got %+v", loaded[0])
 	}
-	if loaded[1].Chip != "nvme-pci-0300" || loaded[1].Temp != 36.9 {
+	if loaded[1].Chip != "nvme-pci-0300" || loaded[1].Temp != 36.9 || loaded[1].Adapter != "Samsung SSD 980 PRO, 1TB" {
 		t.Errorf("second reading: got %+v", loaded[1])
 	}
 }
+
+func TestJSONLRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	SetDataDir(dir)
+	defer SetDataDir("")
+
+	ds := &DiskStore{dir: dir, jsonl: true}
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	readings := []sensor.Reading{
+		{Chip: "coretemp-isa-0000", Adapter: "ISA adapter", Label: "Core 0", Temp: 45.5, High: 101, Crit: 115, HasHigh: true, HasCrit: true, Source: sensor.SourceLMSensors},
+		{Chip: "amdgpu-pci-0600", Adapter: "PCI adapter", Label: "PPT", Temp: 231.5, Unit: "W", Source: sensor.SourceHwmon},
+	}
+	if err := ds.Write(readings, now); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	ds.Close()
+
+	loaded, err := LoadFile(filepath.Join(dir, "2026-02-21.jsonl"))
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(loaded) != len(readings) {
+		t.Fatalf("expected %d readings, got %d", len(readings), len(loaded))
+	}
+	for i, r := range readings {
+		want := StoredReading{Time: now, Chip: r.Chip, Adapter: r.Adapter, Label: r.Label,
+			Temp: r.Temp, High: r.High, Crit: r.Crit, Unit: r.Unit, Source: r.Source}
+		if got := loaded[i]; !got.Time.Equal(want.Time) || got.Adapter != want.Adapter ||
+			got.Temp != want.Temp || got.Crit != want.Crit || got.Unit != want.Unit || got.Source != want.Source {
+			t.Errorf("reading %d: got %+v, want %+v", i, got, want)
+		}
+	}
+
+	// A day written partly as CSV is listed once and loaded whole
+	csvStore := &DiskStore{dir: dir}
+	csvStore.Write(readings[:1], now.Add(-time.Hour))
+	csvStore.Close()
+	if days, _ := ListDays(dir); len(days) != 1 || days[0] != "2026-02-21" {
+		t.Errorf("ListDays = %q, want one day", days)
+	}
+	day, err := LoadDay("2026-02-21")
+	if err != nil || len(day) != 3 || !day[0].Time.Before(day[1].Time) {
+		t.Errorf("LoadDay over both formats: %d readings, %v", len(day), err)
+	}
+}
+
+func TestRecentByKey(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	var readings []StoredReading
+	for i := 0; i < 10; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		readings = append(readings,
+			StoredReading{Time: ts, Chip: "coretemp-isa-0000", Label: "Core 0", Temp: float64(40 + i)},
+			StoredReading{Time: ts, Chip: "nvme-pci-0300", Label: "Composite", Temp: 36},
+		)
+	}
+
+	recent := recentByKey(readings, 3)
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(recent))
+	}
+
+	core := recent["coretemp-isa-0000/Core 0"]
+	if len(core) != 3 {
+		t.Fatalf("expected 3 points for Core 0, got %d", len(core))
+	}
+	if core[0].Temp != 47 || core[2].Temp != 49 {
+		t.Errorf("expected the last 3 points oldest first, got %+v", core)
+	}
+}
+
+func TestEventsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	SetDataDir(dir)
+	defer SetDataDir("")
+
+	start := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	if err := AppendEvent("cpu stress start", start); err != nil {
+		t.Fatalf("AppendEvent: %v", err)
+	}
+	if err := AppendEvent("cpu stress stop", start.Add(time.Minute)); err != nil {
+		t.Fatalf("AppendEvent: %v", err)
+	}
+
+	events, err := LoadEvents("2026-02-21")
+	if err != nil {
+		t.Fatalf("LoadEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].Label != "cpu stress start" || !events[1].Time.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	days, err := ListDays(dir)
+	if err != nil {
+		t.Fatalf("ListDays: %v", err)
+	}
+	if len(days) != 0 {
+		t.Errorf("event files must not be listed as days, got %v", days)
+	}
+
+	if events, err := LoadEvents("2026-02-22"); err != nil || events != nil {
+		t.Errorf("missing event file: got %v, %v", events, err)
+	}
+}
+
+func TestDedupSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+
+	// Two writers appending to the same day file
+	recorder := &DiskStore{dir: dir}
+	monitor := &DiskStore{dir: dir}
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i) * time.Second)
+		recorder.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 45}}, ts)
+		monitor.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 46}}, ts)
+	}
+	recorder.Close()
+	monitor.Close()
+
+	loaded, err := LoadFile(dir + "/2026-02-21.csv")
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(loaded) != 6 {
+		t.Fatalf("expected 6 raw rows, got %d", len(loaded))
+	}
+
+	deduped := Dedup(loaded)
+	if len(deduped) != 3 {
+		t.Fatalf("expected one point per key per second, got %d", len(deduped))
+	}
+	for _, r := range deduped {
+		if r.Temp != 46 {
+			t.Errorf("expected the last written row to win, got %+v", r)
+		}
+	}
+}
+
+func TestLoadFileLegacySchema(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2026-02-20.csv")
+	legacy := "time,chip,label,temp,high,crit\n" +
+		"2026-02-20T10:00:00,coretemp-isa-0000,Core 0,45.0,101.0,115.0\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Appending to a legacy file keeps its 6 columns
+	ds := &DiskStore{dir: dir}
+	at := time.Date(2026, 2, 20, 10, 0, 1, 0, time.Local)
+	if err := ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Adapter: "ISA adapter", Label: "Core 0", Temp: 46, Source: sensor.SourceLMSensors}}, at); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	ds.Close()
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(loaded))
+	}
+	if loaded[0].High != 101 || loaded[1].Temp != 46 || loaded[1].Source != "" || loaded[1].Adapter != "" {
+		t.Errorf("unexpected legacy rows: %+v", loaded)
+	}
+}
+
+func TestLoadFileByColumnName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2026-02-21.csv")
+	reordered := "label,time,temp,chip,source,unit,kind\n" +
+		"Power,2026-02-21T10:00:00,31.5,amdgpu-rocm-0,rocm-smi,W,power\n"
+	if err := os.WriteFile(path, []byte(reordered), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("expected 1 reading, got %d", len(loaded))
+	}
+	r := loaded[0]
+	if r.Chip != "amdgpu-rocm-0" || r.Label != "Power" || r.Temp != 31.5 || r.Unit != "W" || r.Source != sensor.SourceROCm {
+		t.Errorf("unexpected reading: %+v", r)
+	}
+}
+
+func TestNewFileKeepsMilliseconds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "pkgtemp.csv")
+	ds, err := NewFile(path)
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+
+	base := time.Date(2026, 2, 20, 23, 59, 59, 900_000_000, time.Local)
+	r := []sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Package id 0", Temp: 50}}
+	for i := 0; i < 3; i++ {
+		if err := ds.Write(r, base.Add(time.Duration(i)*100*time.Millisecond)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	ds.Close()
+
+	// All rows land in the one file, even across midnight
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("expected 3 readings, got %d", len(loaded))
+	}
+	if got := loaded[1].Time.Sub(loaded[0].Time); got != 100*time.Millisecond {
+		t.Errorf("sample spacing: got %v, want 100ms", got)
+	}
+}
+
+func TestPeaksRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	SetDataDir(dir)
+	defer SetDataDir("")
+
+	peaks, err := LoadPeaks()
+	if err != nil || len(peaks) != 0 {
+		t.Fatalf("missing peaks file: got %v, %v", peaks, err)
+	}
+
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	if !peaks.Observe("nvme-pci-0300/Composite", 52, now) {
+		t.Error("first reading not recorded as a peak")
+	}
+	if peaks.Observe("nvme-pci-0300/Composite", 48, now.Add(time.Second)) {
+		t.Error("cooler reading replaced the peak")
+	}
+	if err := SavePeaks(peaks); err != nil {
+		t.Fatalf("SavePeaks: %v", err)
+	}
+
+	loaded, err := LoadPeaks()
+	if err != nil {
+		t.Fatalf("LoadPeaks: %v", err)
+	}
+	if p := loaded["nvme-pci-0300/Composite"]; p.Temp != 52 || !p.Time.Equal(now) {
+		t.Errorf("unexpected peak after reload: %+v", p)
+	}
+
+	path := filepath.Join(dir, "peaks.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+	if peaks, err := LoadPeaks(); err == nil || len(peaks) != 0 {
+		t.Errorf("corrupt peaks file: got %v, %v", peaks, err)
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("corrupt file not moved aside: %v", err)
+	}
+}
+
+func TestDayRolloverAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	SetLocation(ny)
+	defer SetLocation(nil)
+
+	dir := t.TempDir()
+	ds := &DiskStore{dir: dir}
+	// 2026-03-08 02:00 EST jumps to 03:00 EDT
+	before := time.Date(2026, 3, 7, 23, 59, 0, 0, ny)
+	after := time.Date(2026, 3, 8, 3, 30, 0, 0, ny)
+	// Pass UTC instants: the store, not the caller, picks the day
+	ds.Write([]sensor.Reading{{Chip: "c", Label: "l", Temp: 40}}, before.UTC())
+	ds.Write([]sensor.Reading{{Chip: "c", Label: "l", Temp: 41}}, after.UTC())
+	ds.Close()
+
+	// Loading under a different system zone must not move the readings
+	oldLocal := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = oldLocal }()
+
+	for _, tc := range []struct {
+		day  string
+		want time.Time
+	}{
+		{"2026-03-07", before},
+		{"2026-03-08", after},
+	} {
+		got, err := LoadFile(filepath.Join(dir, tc.day+".csv"))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.day, err)
+		}
+		if len(got) != 1 || !got[0].Time.Equal(tc.want) {
+			t.Errorf("%s: got %v, want one reading at %v", tc.day, got, tc.want)
+		}
+	}
+}
+
+func TestFallBackHourKeepsBothReadings(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	SetLocation(ny)
+	defer SetLocation(nil)
+
+	dir := t.TempDir()
+	ds := &DiskStore{dir: dir}
+	// 2026-11-01 02:00 EDT falls back to 01:00 EST: both are 01:30 local
+	first := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)
+	second := time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)
+	ds.Write([]sensor.Reading{{Chip: "c", Label: "l", Temp: 40}}, first)
+	ds.Write([]sensor.Reading{{Chip: "c", Label: "l", Temp: 41}}, second)
+	ds.Close()
+
+	got, err := LoadFile(filepath.Join(dir, "2026-11-01.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = Dedup(got)
+	if len(got) != 2 || !got[0].Time.Equal(first) || !got[1].Time.Equal(second) {
+		t.Errorf("got %v, want readings at %v and %v", got, first, second)
+	}
+}
+
+func TestLoadLegacyTimestamps(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	SetLocation(ny)
+	defer SetLocation(nil)
+
+	// Rows written before offsets were added are wall-clock time in the
+	// store's location
+	path := filepath.Join(t.TempDir(), "2026-07-01.csv")
+	body := "time,chip,label,temp,high,crit\n2026-07-01T12:00:00,c,l,40,0,0\n2026-07-01T12:00:01.500,c,l,41,0,0\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 7, 1, 12, 0, 0, 0, ny)
+	if len(got) != 2 || !got[0].Time.Equal(want) || !got[1].Time.Equal(want.Add(1500*time.Millisecond)) {
+		t.Errorf("got %v, want readings at %v and 1.5s later", got, want)
+	}
+}
+
+func TestMillisDailyStore(t *testing.T) {
+	SetDataDir(t.TempDir())
+	defer SetDataDir("")
+	SetMillis(true)
+	defer SetMillis(false)
+
+	ds, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 21, 14, 30, 0, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		ds.Write([]sensor.Reading{{Chip: "c", Label: "l", Temp: float64(40 + i)}}, now.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	ds.Close()
+
+	loaded, err := LoadDay("2026-02-21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Dedup(loaded); len(got) != 3 {
+		t.Fatalf("expected 3 samples within one second to survive, got %d", len(got))
+	}
+	if !loaded[1].Time.Equal(now.Add(100 * time.Millisecond)) {
+		t.Errorf("got %v, want millisecond precision", loaded[1].Time)
+	}
+}
+
+func TestClockWindow(t *testing.T) {
+	w, err := ParseClockWindow("14:00", "16:00")
+	if err != nil {
+		t.Fatalf("ParseClockWindow: %v", err)
+	}
+	base := time.Date(2026, 2, 21, 0, 0, 0, 0, location)
+	var readings []StoredReading
+	for h := 12; h <= 18; h++ {
+		readings = append(readings, StoredReading{Time: base.Add(time.Duration(h) * time.Hour), Chip: "coretemp-isa-0000", Label: "Core 0"})
+	}
+	got := w.Filter(readings)
+	if len(got) != 3 || got[0].Time.Hour() != 14 || got[2].Time.Hour() != 16 {
+		t.Errorf("14:00-16:00 kept %d readings: %+v", len(got), got)
+	}
+	if w.String() != "14:00-16:00" {
+		t.Errorf("String() = %q", w.String())
+	}
+
+	open, _ := ParseClockWindow("17:30", "")
+	if got := open.Filter(readings); len(got) != 1 || got[0].Time.Hour() != 18 {
+		t.Errorf("open-ended window kept %+v", got)
+	}
+
+	for _, bad := range [][2]string{{"16:00", "14:00"}, {"2pm", ""}, {"", "25:00"}} {
+		if _, err := ParseClockWindow(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseClockWindow(%q, %q): expected an error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestEnforceSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	SetDataDir(dir)
+	defer SetDataDir("")
+
+	write := func(name string, size int) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("2026-02-19.csv", 400)
+	write("events-2026-02-19.csv", 50)
+	write("2026-02-20.jsonl", 300)
+	write("2026-02-21.csv", 200)
+	write("peaks.json", 50)
+	write("notes.csv", 10)
+
+	days, total, err := PlanSizeCap(600)
+	if err != nil {
+		t.Fatalf("PlanSizeCap: %v", err)
+	}
+	if total != 1010 || len(days) != 1 || days[0] != "2026-02-19" {
+		t.Errorf("plan = %v of %d bytes, want [2026-02-19] of 1010", days, total)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-02-19.csv")); err != nil {
+		t.Error("the plan deleted a file")
+	}
+
+	removed, err := EnforceSizeCap(600)
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("EnforceSizeCap = %v, %v", removed, err)
+	}
+	for _, name := range []string{"2026-02-19.csv", "events-2026-02-19.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s not deleted", name)
+		}
+	}
+
+	// The day being recorded stays even when it alone is over the cap
+	removed, _ = EnforceSizeCap(1)
+	if len(removed) != 1 || removed[0] != "2026-02-20" {
+		t.Errorf("removed %v, want only 2026-02-20", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-02-21.csv")); err != nil {
+		t.Error("newest day deleted")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{"500M": 500 << 20, "2G": 2 << 30, "1.5KiB": 1536, "750000": 750000, "10MB": 10 << 20} {
+		if got, err := ParseSize(in); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "M", "-1G", "lots", "0", "0M", "0.1"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestMedianStep(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	slots := []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second), base.Add(3 * time.Second), base.Add(8 * time.Second)}
+	if got := MedianStep(slots); got != time.Second {
+		t.Errorf("MedianStep: got %v, want 1s", got)
+	}
+	if got := MedianStep(nil); got != time.Second {
+		t.Errorf("MedianStep(nil): got %v, want 1s default", got)
+	}
+}
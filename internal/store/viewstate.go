@@ -0,0 +1,49 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// viewerStateFile holds where the history viewer was left, so the next
+// session can pick up there.
+const viewerStateFile = "viewer.json"
+
+// ViewerState is the day and cursor time the history viewer was closed on.
+type ViewerState struct {
+	Day    string    `json:"day"`
+	Cursor time.Time `json:"cursor"`
+}
+
+// LoadViewerState reads the saved viewer position. A missing or corrupt
+// file yields the zero state: losing it only means starting at the
+// latest day.
+func LoadViewerState() ViewerState {
+	var s ViewerState
+	data, err := os.ReadFile(filepath.Join(DataDir(), viewerStateFile))
+	if err != nil || json.Unmarshal(data, &s) != nil {
+		return ViewerState{}
+	}
+	return s
+}
+
+// SaveViewerState writes the viewer position, replacing the file
+// atomically like SavePeaks.
+func SaveViewerState(s ViewerState) error {
+	dir := DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, viewerStateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
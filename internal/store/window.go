@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockWindow restricts readings to a time of day, e.g. 14:00 to 16:00, on
+// whatever day they were recorded. Both ends are inclusive; a zero Until
+// leaves the window open to the end of the day.
+type ClockWindow struct {
+	Since time.Duration // from midnight
+	Until time.Duration // from midnight; 0 means end of day
+}
+
+// ParseClockWindow parses --since and --until values as HH:MM or
+// HH:MM:SS. Either may be empty.
+func ParseClockWindow(since, until string) (ClockWindow, error) {
+	var w ClockWindow
+	var err error
+	if since != "" {
+		if w.Since, err = parseClock(since); err != nil {
+			return ClockWindow{}, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if until != "" {
+		if w.Until, err = parseClock(until); err != nil {
+			return ClockWindow{}, fmt.Errorf("--until: %w", err)
+		}
+		if w.Until <= w.Since {
+			return ClockWindow{}, fmt.Errorf("--until %s is not after --since", until)
+		}
+	}
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return clockOffset(t), nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a time of day like 14:00", s)
+}
+
+// clockOffset is t's wall-clock time since midnight. It is read from the
+// clock fields rather than subtracted, so DST days are not off by an hour.
+func clockOffset(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+// IsZero reports whether the window lets every reading through.
+func (w ClockWindow) IsZero() bool {
+	return w == ClockWindow{}
+}
+
+// Contains reports whether t falls in the window, judged in the store's
+// location.
+func (w ClockWindow) Contains(t time.Time) bool {
+	off := clockOffset(t.In(location))
+	return off >= w.Since && (w.Until == 0 || off <= w.Until)
+}
+
+// Filter returns the readings inside the window.
+func (w ClockWindow) Filter(readings []StoredReading) []StoredReading {
+	if w.IsZero() {
+		return readings
+	}
+	var out []StoredReading
+	for _, r := range readings {
+		if w.Contains(r.Time) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// String describes the window for messages, e.g. "14:00-16:00".
+func (w ClockWindow) String() string {
+	end := "24:00"
+	if w.Until != 0 {
+		end = fmtClock(w.Until)
+	}
+	return fmtClock(w.Since) + "-" + end
+}
+
+func fmtClock(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	h, m, s := int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60
+	if s != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
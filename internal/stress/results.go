@@ -0,0 +1,321 @@
+package stress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/luki/sensors/internal/sensor"
+)
+
+// logResults appends each benchmark's one-line summary to the event log,
+// next to the start/stop markers, so results can be read alongside temps.
+var logResults bool
+
+// summary is the parsed outcome of one benchmark run.
+type summary struct {
+	tool  string
+	lines []string // rows printed under the heading
+	brief string   // single line for the event log
+}
+
+func (s summary) print() {
+	fmt.Printf("\n  Results (%s)\n", s.tool)
+	for _, l := range s.lines {
+		fmt.Println("    " + l)
+	}
+}
+
+// bench describes how to get machine-readable results from a tool.
+type bench struct {
+	tool       string
+	jsonFlag   string // appended to the arguments when supported
+	streamFlag string // when tracing, makes the tool print a JSON document per interval
+	parse      func([]byte) (summary, error)
+	newSampler func() func(doc []byte) []sensor.Reading // metrics from one interval document
+}
+
+var (
+	fioBench = bench{
+		tool:       "fio",
+		jsonFlag:   "--output-format=json",
+		streamFlag: "--status-interval=1",
+		parse:      parseFio,
+		newSampler: newFioSampler,
+	}
+	iperfBench = bench{
+		tool:       "iperf3",
+		jsonFlag:   "-J",
+		streamFlag: "--json-stream",
+		parse:      parseIperf,
+		newSampler: newIperfSampler,
+	}
+)
+
+// help returns the tool's usage text. It goes to stdout or stderr
+// depending on the tool and version, and some exit non-zero after
+// printing it.
+func (b bench) help() []byte {
+	out, _ := exec.Command(b.tool, "--help").CombinedOutput()
+	return out
+}
+
+// documents reports whether usage text lists a flag, ignoring its value.
+func documents(help []byte, flag string) bool {
+	if i := strings.IndexByte(flag, '='); i >= 0 {
+		flag = flag[:i]
+	}
+	return bytes.Contains(help, []byte(flag))
+}
+
+// runBench runs a benchmark with JSON output and prints a summary of it
+// instead of the raw report. While tracing, interval reports are turned
+// into metrics for the trace as they arrive. Without JSON support the
+// tool runs as usual; output that fails to parse is shown raw.
+func runBench(sigCh chan os.Signal, dryRun bool, label string, b bench, args ...string) {
+	help := b.help()
+	if !documents(help, b.jsonFlag) {
+		runCmd(sigCh, dryRun, b.tool, args...)
+		return
+	}
+	args = append(args, b.jsonFlag)
+
+	var out bytes.Buffer
+	if trace == nil || !documents(help, b.streamFlag) {
+		runCmdTo(&out, sigCh, dryRun, b.tool, args...)
+	} else {
+		pr, pw := io.Pipe()
+		streamed := make(chan struct{})
+		go func() {
+			defer close(streamed)
+			sample := b.newSampler()
+			jsonDocs(pr, func(doc []byte) { trace.publish(sample(doc)) })
+			io.Copy(io.Discard, pr)
+		}()
+		runCmdTo(io.MultiWriter(&out, pw), sigCh, dryRun, b.tool, append(args, b.streamFlag)...)
+		pw.Close()
+		<-streamed
+	}
+	if dryRun {
+		return
+	}
+
+	s, err := b.parse(lastDoc(out.Bytes()))
+	if err != nil {
+		os.Stdout.Write(out.Bytes())
+		fmt.Fprintf(os.Stderr, "  could not summarize %s output: %v\n", b.tool, err)
+		return
+	}
+	s.print()
+	if logResults {
+		logEvent(label + " " + s.tool + " " + s.brief)
+	}
+}
+
+// ── fio ──────────────────────────────────────────────────────────────
+
+type fioIO struct {
+	IOPS  float64 `json:"iops"`
+	BW    float64 `json:"bw"` // KiB/s
+	LatNs struct {
+		Mean float64 `json:"mean"`
+	} `json:"lat_ns"`
+}
+
+type fioOutput struct {
+	Jobs []struct {
+		Read  fioIO `json:"read"`
+		Write fioIO `json:"write"`
+	} `json:"jobs"`
+}
+
+// parseFio summarizes fio's JSON report. Jobs are expected to use
+// --group_reporting, so the first job holds the totals.
+func parseFio(out []byte) (summary, error) {
+	var o fioOutput
+	if err := json.Unmarshal(out, &o); err != nil {
+		return summary{}, err
+	}
+	if len(o.Jobs) == 0 {
+		return summary{}, fmt.Errorf("no jobs in report")
+	}
+
+	s := summary{tool: "fio"}
+	var brief []string
+	for _, dir := range []struct {
+		name string
+		io   fioIO
+	}{{"read", o.Jobs[0].Read}, {"write", o.Jobs[0].Write}} {
+		if dir.io.IOPS == 0 {
+			continue
+		}
+		bw := fmtBytes(uint64(dir.io.BW*1024)) + "/s"
+		lat := fmtLatency(dir.io.LatNs.Mean)
+		s.lines = append(s.lines, fmt.Sprintf("%-5s  %8.0f IOPS  %9s  lat %s", dir.name, dir.io.IOPS, bw, lat))
+		brief = append(brief, fmt.Sprintf("%s %.0f IOPS %s lat %s", dir.name, dir.io.IOPS, bw, lat))
+	}
+	if len(brief) == 0 {
+		return summary{}, fmt.Errorf("no I/O in report")
+	}
+	s.brief = strings.Join(brief, ", ")
+	return s, nil
+}
+
+// fmtLatency formats nanoseconds with a unit suited to the magnitude.
+func fmtLatency(ns float64) string {
+	switch {
+	case ns >= 1e6:
+		return fmt.Sprintf("%.1fms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.0fµs", ns/1e3)
+	}
+	return fmt.Sprintf("%.0fns", ns)
+}
+
+// jsonDocs calls fn with each top-level JSON document in r, skipping the
+// text tools print around them, such as fio's notes about options. A
+// document starts with "{" at the start of a line and ends at a line
+// starting with "}", or on the same line for line-delimited output.
+func jsonDocs(r io.Reader, fn func(doc []byte)) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var doc []byte
+	for sc.Scan() {
+		line := sc.Bytes()
+		if doc == nil && !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		doc = append(doc, line...)
+		doc = append(doc, '\n')
+		if (bytes.HasPrefix(line, []byte("}")) || len(doc) == len(line)+1) && json.Valid(doc) {
+			fn(doc)
+			doc = nil
+		}
+	}
+}
+
+// lastDoc returns the final JSON document in out, the end-of-run report
+// when a tool also printed interval reports. Output without one is
+// returned whole so the parse error shows what went wrong.
+func lastDoc(out []byte) []byte {
+	last := out
+	jsonDocs(bytes.NewReader(out), func(doc []byte) { last = doc })
+	return last
+}
+
+// fioTotals are the running counters of one direction in a fio report.
+type fioTotals struct {
+	IOs     float64 `json:"total_ios"`
+	Bytes   float64 `json:"io_bytes"`
+	Runtime float64 `json:"runtime"` // ms
+}
+
+// newFioSampler returns a sampler that turns fio's cumulative status
+// reports into IOPS and bandwidth over each interval.
+func newFioSampler() func([]byte) []sensor.Reading {
+	var prev [2]fioTotals
+	return func(doc []byte) []sensor.Reading {
+		var o struct {
+			Jobs []struct {
+				Read  fioTotals `json:"read"`
+				Write fioTotals `json:"write"`
+			} `json:"jobs"`
+		}
+		if json.Unmarshal(doc, &o) != nil || len(o.Jobs) == 0 {
+			return nil
+		}
+		var rs []sensor.Reading
+		for i, dir := range []struct {
+			name string
+			cur  fioTotals
+		}{{"read", o.Jobs[0].Read}, {"write", o.Jobs[0].Write}} {
+			ms := dir.cur.Runtime - prev[i].Runtime
+			if ms > 0 {
+				rs = append(rs,
+					metricReading("fio", dir.name+" IOPS", (dir.cur.IOs-prev[i].IOs)*1000/ms, "IOPS"),
+					metricReading("fio", dir.name+" MB/s", (dir.cur.Bytes-prev[i].Bytes)/1000/ms, "MB/s"))
+			}
+			prev[i] = dir.cur
+		}
+		return rs
+	}
+}
+
+// ── iperf3 ───────────────────────────────────────────────────────────
+
+// iperfEvent is one line of iperf3 --json-stream output. The "end"
+// event's data is the "end" object of a -J report.
+type iperfEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// newIperfSampler returns a sampler for --json-stream interval events.
+func newIperfSampler() func([]byte) []sensor.Reading {
+	return func(doc []byte) []sensor.Reading {
+		var ev iperfEvent
+		if json.Unmarshal(doc, &ev) != nil || ev.Event != "interval" {
+			return nil
+		}
+		var data struct {
+			Sum struct {
+				BitsPerSecond float64 `json:"bits_per_second"`
+			} `json:"sum"`
+		}
+		if json.Unmarshal(ev.Data, &data) != nil {
+			return nil
+		}
+		return []sensor.Reading{metricReading("iperf3", "throughput", data.Sum.BitsPerSecond/1e6, "Mbit/s")}
+	}
+}
+
+type iperfOutput struct {
+	End struct {
+		SumSent struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+			Retransmits   int     `json:"retransmits"`
+		} `json:"sum_sent"`
+		SumReceived struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+		} `json:"sum_received"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+// parseIperf summarizes a -J report, or the final event of --json-stream
+// output.
+func parseIperf(out []byte) (summary, error) {
+	var o iperfOutput
+	var ev iperfEvent
+	if err := json.Unmarshal(out, &ev); err != nil {
+		return summary{}, err
+	}
+	switch ev.Event {
+	case "":
+		json.Unmarshal(out, &o)
+	case "end":
+		if err := json.Unmarshal(ev.Data, &o.End); err != nil {
+			return summary{}, err
+		}
+	case "error":
+		json.Unmarshal(ev.Data, &o.Error)
+	}
+	sent, recv := o.End.SumSent.BitsPerSecond/1e6, o.End.SumReceived.BitsPerSecond/1e6
+	// An interrupted client reports an error but still sums what it sent
+	if o.Error != "" && sent == 0 {
+		return summary{}, fmt.Errorf("%s", o.Error)
+	}
+	return summary{
+		tool: "iperf3",
+		lines: []string{
+			fmt.Sprintf("sent      %8.1f Mbit/s  (%d retransmits)", sent, o.End.SumSent.Retransmits),
+			fmt.Sprintf("received  %8.1f Mbit/s", recv),
+		},
+		brief: fmt.Sprintf("sent %.1f Mbit/s, received %.1f Mbit/s, %d retransmits", sent, recv, o.End.SumSent.Retransmits),
+	}, nil
+}
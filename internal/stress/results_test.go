@@ -0,0 +1,108 @@
+package stress
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFio(t *testing.T) {
+	out := `note: both iodepth >= 1 and synchronous I/O engine are selected
+{
+  "fio version" : "fio-3.36",
+  "jobs" : [
+    {
+      "jobname" : "nvme-stress",
+      "read" : {"iops" : 52341.5, "bw" : 209366, "lat_ns" : {"mean" : 1204000.0}},
+      "write" : {"iops" : 52310.2, "bw" : 209240, "lat_ns" : {"mean" : 1310500.0}}
+    }
+  ]
+}`
+	s, err := parseFio(lastDoc([]byte(out)))
+	if err != nil {
+		t.Fatalf("parseFio: %v", err)
+	}
+	want := "read 52342 IOPS 204.5M/s lat 1.2ms, write 52310 IOPS 204.3M/s lat 1.3ms"
+	if s.brief != want {
+		t.Errorf("brief = %q, want %q", s.brief, want)
+	}
+	if len(s.lines) != 2 || !strings.HasPrefix(s.lines[0], "read ") {
+		t.Errorf("lines = %q", s.lines)
+	}
+
+	if _, err := parseFio(lastDoc([]byte("fio: unrecognized option"))); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestParseIperfStream(t *testing.T) {
+	out := `{"event":"start","data":{}}
+{"event":"interval","data":{"sum":{"bits_per_second":512000000}}}
+{"event":"end","data":{"sum_sent":{"bits_per_second":500000000,"retransmits":0},"sum_received":{"bits_per_second":499000000}}}
+`
+	var metrics []float64
+	sample := newIperfSampler()
+	jsonDocs(strings.NewReader(out), func(doc []byte) {
+		for _, r := range sample(doc) {
+			metrics = append(metrics, r.Temp)
+		}
+	})
+	if len(metrics) != 1 || metrics[0] != 512 {
+		t.Errorf("interval metrics = %v, want [512]", metrics)
+	}
+
+	s, err := parseIperf(lastDoc([]byte(out)))
+	if err != nil || s.brief != "sent 500.0 Mbit/s, received 499.0 Mbit/s, 0 retransmits" {
+		t.Errorf("end event: %q, %v", s.brief, err)
+	}
+}
+
+func TestParseIperf(t *testing.T) {
+	out := `{"end": {"sum_sent": {"bits_per_second": 941200000, "retransmits": 12},
+	"sum_received": {"bits_per_second": 939800000}},
+	"error": "interrupt - the client has terminated"}`
+	s, err := parseIperf([]byte(out))
+	if err != nil {
+		t.Fatalf("an interrupted run with totals should still parse: %v", err)
+	}
+	if want := "sent 941.2 Mbit/s, received 939.8 Mbit/s, 12 retransmits"; s.brief != want {
+		t.Errorf("brief = %q, want %q", s.brief, want)
+	}
+
+	if _, err := parseIperf([]byte(`{"error": "unable to connect to server"}`)); err == nil {
+		t.Error("expected the iperf3 error without totals")
+	}
+}
+
+func TestFioSamplerIntervals(t *testing.T) {
+	// Two cumulative status reports one second apart, then the final one
+	out := `{
+  "jobs" : [{"read" : {"total_ios" : 1000, "io_bytes" : 4096000, "runtime" : 1000}, "write" : {}}]
+}
+fio: note: interval report
+{
+  "jobs" : [{"read" : {"total_ios" : 3000, "io_bytes" : 12288000, "runtime" : 2000}, "write" : {}}]
+}
+{
+  "jobs" : [{"read" : {"iops" : 1500, "bw" : 6000, "lat_ns" : {"mean" : 800}}, "write" : {}}]
+}
+`
+	var docs [][]byte
+	jsonDocs(strings.NewReader(out), func(doc []byte) { docs = append(docs, doc) })
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(docs))
+	}
+
+	sample := newFioSampler()
+	sample(docs[0])
+	rs := sample(docs[1])
+	if len(rs) != 2 || rs[0].Label != "read IOPS" || rs[0].Temp != 2000 || rs[1].Temp != 8.192 {
+		t.Errorf("interval metrics = %+v, want 2000 IOPS and 8.192 MB/s", rs)
+	}
+	if rs[0].Chip != metricChip || rs[0].Unit != "IOPS" {
+		t.Errorf("metric reading = %+v", rs[0])
+	}
+
+	if s, err := parseFio(lastDoc([]byte(out))); err != nil || !strings.HasPrefix(s.brief, "read 1500 IOPS") {
+		t.Errorf("final report: %q, %v", s.brief, err)
+	}
+}
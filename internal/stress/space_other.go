@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd)
+
+package stress
+
+// diskSpace is not implemented here; ok is false and the free-space check
+// is skipped.
+func diskSpace(path string) (free, total uint64, ok bool, err error) {
+	return 0, 0, false, nil
+}
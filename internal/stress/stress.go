@@ -3,15 +3,26 @@
 package stress
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"github.com/luki/sensors/internal/store"
 )
 
 var targets = []struct {
@@ -22,12 +33,17 @@ var targets = []struct {
 	{"gpu", "NVIDIA GPU compute (nvidia-smi)"},
 	{"nvme", "NVMe SSD random read/write (fio)"},
 	{"disk", "SATA HDD sequential I/O (fio)"},
-	{"wifi", "Network interface flood (ping flood)"},
+	{"wifi", "Network interface flood (iperf3, HTTP downloads or ping flood)"},
 	{"all", "Everything at once"},
 }
 
-// Run dispatches the stress test based on command-line arguments.
-func Run(args []string) {
+// forever is the run length, in seconds, of an indefinite run that only
+// stops on Ctrl+C.
+const forever = 0
+
+// Run dispatches the stress test based on command-line arguments. Runs
+// without a duration argument last defaultDuration.
+func Run(args []string, defaultDuration time.Duration) {
 	if len(args) == 0 {
 		printHelp()
 		return
@@ -35,11 +51,46 @@ func Run(args []string) {
 
 	target := strings.ToLower(args[0])
 
-	duration := 60 * time.Second
-	if len(args) > 1 {
-		if d, err := time.ParseDuration(args[1]); err == nil {
+	fs := flag.NewFlagSet("stress", flag.ContinueOnError)
+	untilInterrupt := fs.Bool("until-interrupt", false, "run until Ctrl+C")
+	dryRun := fs.Bool("dry-run", false, "print the tools, devices and files each target would use, then exit")
+	fs.BoolVar(&logResults, "log-results", false, "append fio/iperf3 result summaries to the event log")
+	tracePath := fs.String("trace", "", "write temps, CPU load and fio/iperf3 metrics every second to this CSV")
+	cpus := fs.Int("cpus", 0, "CPU cores to load for cpu and all (default all cores)")
+	load := fs.String("load", "100%", "busy share of each loaded core for cpu and all, e.g. 50%")
+	var netOpts netOptions
+	fs.StringVar(&netOpts.target, "target", "", "iperf3 server to stress the network link against")
+	fs.StringVar(&netOpts.bitrate, "bitrate", "", "iperf3 target bitrate per stream, e.g. 100M (default unlimited)")
+	fs.StringVar(&netOpts.url, "url", "", "file to download in parallel when iperf3 is unavailable")
+
+	// Flags may come before or after the duration
+	var positional []string
+	rest := args[1:]
+	for {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(2)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	cpuOpts, err := parseCPUOptions(*cpus, *load)
+	if err == nil {
+		err = netOpts.validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	duration := defaultDuration
+	if len(positional) > 0 {
+		if d, err := time.ParseDuration(positional[0]); err == nil {
 			duration = d
-		} else if secs, err := strconv.Atoi(args[1]); err == nil {
+		} else if secs, err := strconv.Atoi(positional[0]); err == nil {
 			duration = time.Duration(secs) * time.Second
 		}
 	}
@@ -48,27 +99,69 @@ func Run(args []string) {
 	if durSecs < 1 {
 		durSecs = 60
 	}
+	if *untilInterrupt || (len(positional) > 0 && isIndefinite(positional[0])) {
+		durSecs = forever
+	}
 
-	fmt.Printf("Stressing: %s for %ds\n", target, durSecs)
-	fmt.Println("Press Ctrl+C to stop early")
+	if *dryRun {
+		fmt.Printf("Plan: %s %s (dry run, nothing is started)\n", target, forDuration(durSecs))
+	} else if durSecs == forever {
+		fmt.Printf("Stressing: %s until interrupted\n", target)
+		fmt.Println("Press Ctrl+C to stop")
+	} else {
+		fmt.Printf("Stressing: %s for %ds\n", target, durSecs)
+		fmt.Println("Press Ctrl+C to stop early")
+	}
 	fmt.Println()
 
+	// SIGHUP too, so closing the terminal still stops the children and
+	// removes fio's scratch files
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	// Bracket each run with start/stop markers so the history viewer can
+	// show how temperatures responded
+	run := func(fn func(int, chan os.Signal)) {
+		if *dryRun {
+			if *tracePath != "" {
+				fmt.Printf("  would trace to %s\n", *tracePath)
+			}
+			fn(durSecs, sigCh)
+			return
+		}
+		if *tracePath != "" {
+			ctx, cancel := context.WithCancel(context.Background())
+			t, err := startTrace(ctx, *tracePath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: trace: %v\n", err)
+				os.Exit(1)
+			}
+			trace = t
+			defer func() {
+				cancel()
+				t.wait()
+			}()
+		}
+		logEvent(target + " stress start")
+		fn(durSecs, sigCh)
+		logEvent(target + " stress stop")
+	}
 
+	// A dry run is passed down to every runner, so nothing it plans
+	// touches the filesystem or starts a process
 	switch target {
 	case "cpu":
-		stressCPU(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressCPU(secs, sigCh, cpuOpts, *dryRun) })
 	case "gpu":
-		stressGPU(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressGPU(secs, sigCh, *dryRun) })
 	case "nvme":
-		stressNVMe(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressNVMe(secs, sigCh, *dryRun) })
 	case "disk":
-		stressDisk(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressDisk(secs, sigCh, *dryRun) })
 	case "wifi", "net", "network":
-		stressWifi(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressWifi(secs, sigCh, netOpts, *dryRun) })
 	case "all":
-		stressAll(durSecs, sigCh)
+		run(func(secs int, sigCh chan os.Signal) { stressAll(secs, sigCh, cpuOpts, netOpts, *dryRun) })
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown target: %s\n\n", target)
 		printHelp()
@@ -77,44 +170,133 @@ func Run(args []string) {
 }
 
 func printHelp() {
-	fmt.Println("Usage: sensors stress <target> [duration]")
+	fmt.Println("Usage: sensors stress <target> [duration | inf] [--cpus N] [--load P%]")
 	fmt.Println()
 	fmt.Println("Targets:")
 	for _, t := range targets {
 		fmt.Printf("  %-8s  %s\n", t.name, t.desc)
 	}
 	fmt.Println()
-	fmt.Println("Duration: e.g. '60' (seconds), '2m', '30s' (default: 60s)")
+	fmt.Println("Duration: e.g. '60' (seconds), '2m', '30s' (default: 60s, or stress_duration")
+	fmt.Println("in the config); 'inf' or --until-interrupt runs until Ctrl+C")
+	fmt.Println()
+	fmt.Println("CPU load: --cpus N loads N cores, --load P% keeps each busy P% of the")
+	fmt.Println("time (cpu and all targets)")
+	fmt.Println()
+	fmt.Println("Network: --target HOST runs iperf3 against a real server so the traffic")
+	fmt.Println("crosses the NIC, capped per stream by --bitrate; without iperf3, --url")
+	fmt.Println("is downloaded in parallel. With neither, iperf3 runs over loopback.")
+	fmt.Println()
+	fmt.Println("--dry-run prints the tools, devices, file sizes and durations each target")
+	fmt.Println("would use without starting anything")
+	fmt.Println()
+	fmt.Println("fio and iperf3 results are summarized at the end (IOPS, bandwidth, latency);")
+	fmt.Println("--log-results also appends them to the event log")
+	fmt.Println()
+	fmt.Println("--trace FILE writes temps, CPU load and live fio/iperf3 IOPS and bandwidth")
+	fmt.Println("to one CSV every second, ready to plot (or open with history --file)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  sensors stress cpu 30s")
 	fmt.Println("  sensors stress gpu 2m")
 	fmt.Println("  sensors stress all 60")
+	fmt.Println("  sensors stress cpu inf")
+	fmt.Println("  sensors stress cpu 5m --cpus 4 --load 50%")
+	fmt.Println("  sensors stress wifi 5m --target 192.168.1.10 --bitrate 200M")
+	fmt.Println("  sensors stress all --dry-run")
+	fmt.Println("  sensors stress nvme 10m --trace nvme-soak.csv")
+}
+
+// isIndefinite reports whether a duration argument asks to run until
+// interrupted.
+func isIndefinite(arg string) bool {
+	switch strings.ToLower(arg) {
+	case "inf", "forever":
+		return true
+	}
+	return false
+}
+
+// timeout fires after secs, or never for a run lasting forever.
+func timeout(secs int) <-chan time.Time {
+	if secs == forever {
+		return nil
+	}
+	return time.After(time.Duration(secs) * time.Second)
+}
+
+// forDuration describes a run length for progress output.
+func forDuration(secs int) string {
+	if secs == forever {
+		return "until interrupted"
+	}
+	return fmt.Sprintf("for %ds", secs)
 }
 
 // ── CPU stress ───────────────────────────────────────────────────────
 
-func stressCPU(secs int, sigCh chan os.Signal) {
+// cpuOptions limits the CPU stress to a number of cores and a partial
+// load, for thermals closer to real workloads than every core pegged.
+type cpuOptions struct {
+	cpus int // cores to load
+	load int // percent of the time each loaded core is busy, 1-100
+}
+
+// parseCPUOptions validates --cpus and --load. Zero cpus means all cores,
+// and more than the machine has is refused rather than quietly lowered;
+// load may be given with or without a percent sign.
+func parseCPUOptions(cpus int, load string) (cpuOptions, error) {
+	if cpus < 0 {
+		return cpuOptions{}, fmt.Errorf("--cpus must not be negative, got %d", cpus)
+	}
+	if n := runtime.NumCPU(); cpus > n {
+		return cpuOptions{}, fmt.Errorf("--cpus %d is more than the %d cores of this machine", cpus, n)
+	}
+	if cpus == 0 {
+		cpus = runtime.NumCPU()
+	}
+	pct, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(load), "%"))
+	if err != nil || pct < 1 || pct > 100 {
+		return cpuOptions{}, fmt.Errorf("--load must be a percentage from 1%% to 100%%, got %q", load)
+	}
+	return cpuOptions{cpus: cpus, load: pct}, nil
+}
+
+func stressCPU(secs int, sigCh chan os.Signal, opts cpuOptions, dryRun bool) {
 	if !checkTool("stress-ng") {
 		fmt.Println("stress-ng not found, using built-in CPU burner")
-		cpuBurnFallback(secs, sigCh)
+		cpuBurnFallback(secs, sigCh, opts, dryRun)
 		return
 	}
 
-	cpus := runtime.NumCPU()
-	fmt.Printf("  stress-ng --cpu %d --timeout %ds\n", cpus, secs)
-	runCmd(sigCh, "stress-ng", "--cpu", strconv.Itoa(cpus), "--timeout", fmt.Sprintf("%ds", secs))
+	// stress-ng treats a zero timeout as no timeout
+	fmt.Printf("  stress-ng --cpu %d --cpu-load %d --timeout %ds\n", opts.cpus, opts.load, secs)
+	runCmd(sigCh, dryRun, "stress-ng", "--cpu", strconv.Itoa(opts.cpus), "--cpu-load", strconv.Itoa(opts.load),
+		"--timeout", fmt.Sprintf("%ds", secs))
 }
 
-func cpuBurnFallback(secs int, sigCh chan os.Signal) {
-	cpus := runtime.NumCPU()
-	fmt.Printf("  burning %d cores for %ds\n", cpus, secs)
+// dutyPeriod is the busy/idle cycle of the built-in burner at partial
+// load, short enough that the load reads as steady.
+const dutyPeriod = 100 * time.Millisecond
+
+// busyShare is how long each burner spins per dutyPeriod at load percent.
+func busyShare(load int) time.Duration {
+	return dutyPeriod * time.Duration(load) / 100
+}
+
+func cpuBurnFallback(secs int, sigCh chan os.Signal, opts cpuOptions, dryRun bool) {
+	cpus := opts.cpus
+	fmt.Printf("  burning %d cores at %d%% %s\n", cpus, opts.load, forDuration(secs))
+	busy := busyShare(opts.load)
+	if dryRun {
+		return
+	}
 
 	done := make(chan struct{})
 	go func() {
 		select {
 		case <-sigCh:
-		case <-time.After(time.Duration(secs) * time.Second):
+		case <-timeout(secs):
 		}
 		close(done)
 	}()
@@ -123,12 +305,24 @@ func cpuBurnFallback(secs int, sigCh chan os.Signal) {
 		go func() {
 			x := 0.0
 			for {
+				// Spin for the busy share of each period, then sleep out the rest
+				cycle := time.Now()
+				for time.Since(cycle) < busy {
+					select {
+					case <-done:
+						return
+					default:
+						x += 1.1
+						x *= 0.9
+					}
+				}
+				if busy == dutyPeriod {
+					continue
+				}
 				select {
 				case <-done:
 					return
-				default:
-					x += 1.1
-					x *= 0.9
+				case <-time.After(dutyPeriod - time.Since(cycle)):
 				}
 			}
 		}()
@@ -140,15 +334,15 @@ func cpuBurnFallback(secs int, sigCh chan os.Signal) {
 
 // ── GPU stress ───────────────────────────────────────────────────────
 
-func stressGPU(secs int, sigCh chan os.Signal) {
-	fmt.Printf("  GPU stress for %ds\n", secs)
+func stressGPU(secs int, sigCh chan os.Signal, dryRun bool) {
+	fmt.Printf("  GPU stress %s\n", forDuration(secs))
 
 	if checkTool("glmark2") {
 		fmt.Println("  glmark2 (OpenGL rendering benchmark)")
 		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
 			fmt.Println("  note: needs a graphical session (DISPLAY or WAYLAND_DISPLAY)")
 		}
-		runCmdWithTimeout(secs, sigCh, "glmark2", "--run-forever")
+		runCmdWithTimeout(secs, sigCh, dryRun, "glmark2", "--run-forever")
 		return
 	}
 
@@ -157,18 +351,21 @@ func stressGPU(secs int, sigCh chan os.Signal) {
 		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
 			fmt.Println("  note: needs a graphical session (DISPLAY or WAYLAND_DISPLAY)")
 		}
-		runCmdWithTimeout(secs, sigCh, "glxgears")
+		runCmdWithTimeout(secs, sigCh, dryRun, "glxgears")
 		return
 	}
 
 	if checkTool("nvidia-smi") {
 		fmt.Println("  nvidia-smi query loop (light GPU load)")
 		fmt.Println("  tip: install glmark2 for real GPU stress: sudo pacman -S glmark2")
+		if dryRun {
+			return
+		}
 		done := make(chan struct{})
 		go func() {
 			select {
 			case <-sigCh:
-			case <-time.After(time.Duration(secs) * time.Second):
+			case <-timeout(secs):
 			}
 			close(done)
 		}()
@@ -190,7 +387,7 @@ func stressGPU(secs int, sigCh chan os.Signal) {
 
 // ── NVMe stress ──────────────────────────────────────────────────────
 
-func stressNVMe(secs int, sigCh chan os.Signal) {
+func stressNVMe(secs int, sigCh chan os.Signal, dryRun bool) {
 	if !checkTool("fio") {
 		fmt.Fprintln(os.Stderr, "  fio not found -- install: sudo pacman -S fio")
 		return
@@ -202,14 +399,19 @@ func stressNVMe(secs int, sigCh chan os.Signal) {
 		return
 	}
 
-	fmt.Printf("  fio random read/write on %s for %ds\n", dev, secs)
-	fmt.Println("  (using temp file, safe -- no raw device writes)")
-
 	tmpDir := "/tmp/sensors-stress-nvme"
-	os.MkdirAll(tmpDir, 0755)
-	defer os.RemoveAll(tmpDir)
+	if err := prepareScratch(tmpDir, 4<<30, dryRun); err != nil { // 4 jobs x 1G
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+	if !dryRun {
+		defer os.RemoveAll(tmpDir)
+	}
 
-	runCmd(sigCh, "fio",
+	fmt.Printf("  fio random read/write on %s %s\n", dev, forDuration(secs))
+	fmt.Println("  (using temp file, safe -- no raw device writes)")
+
+	runBench(sigCh, dryRun, "nvme", fioBench,
 		"--name=nvme-stress",
 		"--directory="+tmpDir,
 		"--rw=randrw",
@@ -219,7 +421,7 @@ func stressNVMe(secs int, sigCh chan os.Signal) {
 		"--iodepth=32",
 		"--ioengine=libaio",
 		"--direct=1",
-		"--runtime="+strconv.Itoa(secs),
+		fioRuntime(secs),
 		"--time_based",
 		"--group_reporting",
 	)
@@ -227,19 +429,24 @@ func stressNVMe(secs int, sigCh chan os.Signal) {
 
 // ── Disk (SATA) stress ───────────────────────────────────────────────
 
-func stressDisk(secs int, sigCh chan os.Signal) {
+func stressDisk(secs int, sigCh chan os.Signal, dryRun bool) {
 	if !checkTool("fio") {
 		fmt.Fprintln(os.Stderr, "  fio not found -- install: sudo pacman -S fio")
 		return
 	}
 
-	fmt.Printf("  fio sequential I/O on /tmp for %ds\n", secs)
-
 	tmpDir := "/tmp/sensors-stress-disk"
-	os.MkdirAll(tmpDir, 0755)
-	defer os.RemoveAll(tmpDir)
+	if err := prepareScratch(tmpDir, 1<<30, dryRun); err != nil { // 2 jobs x 512M
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+	if !dryRun {
+		defer os.RemoveAll(tmpDir)
+	}
+
+	fmt.Printf("  fio sequential I/O on /tmp %s\n", forDuration(secs))
 
-	runCmd(sigCh, "fio",
+	runBench(sigCh, dryRun, "disk", fioBench,
 		"--name=disk-stress",
 		"--directory="+tmpDir,
 		"--rw=readwrite",
@@ -249,35 +456,207 @@ func stressDisk(secs int, sigCh chan os.Signal) {
 		"--iodepth=8",
 		"--ioengine=libaio",
 		"--direct=1",
-		"--runtime="+strconv.Itoa(secs),
+		fioRuntime(secs),
 		"--time_based",
 		"--group_reporting",
 	)
 }
 
+// prepareScratch creates fio's scratch directory, first removing files a
+// previous run left behind when it was killed too hard to clean up. It
+// refuses when the files would leave less than a tenth of the filesystem
+// free: on a tmpfs /tmp that space is RAM. A dry run only checks and
+// prints.
+func prepareScratch(dir string, need uint64, dryRun bool) error {
+	if !dryRun {
+		os.RemoveAll(dir)
+	}
+
+	free, total, ok, err := diskSpace(filepath.Dir(dir))
+	if err != nil {
+		return fmt.Errorf("checking free space: %w", err)
+	}
+	reserve := total / 10
+	if ok && free < need+reserve {
+		return fmt.Errorf("not enough space in %s: fio needs %s, %s free (keeping %s spare)",
+			filepath.Dir(dir), fmtBytes(need), fmtBytes(free), fmtBytes(reserve))
+	}
+	if dryRun {
+		if ok {
+			fmt.Printf("  scratch files: %s in %s (%s free)\n", fmtBytes(need), dir, fmtBytes(free))
+		} else {
+			fmt.Printf("  scratch files: %s in %s\n", fmtBytes(need), dir)
+		}
+		return nil
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// fmtBytes formats a byte count in binary units, e.g. "1.5G".
+func fmtBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// fioRuntime bounds a time_based fio job. fio reads a zero runtime as no
+// limit, looping over its files until stopped.
+func fioRuntime(secs int) string {
+	if secs == forever {
+		return "--runtime=0"
+	}
+	return "--runtime=" + strconv.Itoa(secs)
+}
+
 // ── WiFi / network stress ────────────────────────────────────────────
 
-func stressWifi(secs int, sigCh chan os.Signal) {
-	fmt.Printf("  Network stress for %ds\n", secs)
+// netOptions point the network stress at a real endpoint, so the traffic
+// heats the NIC instead of staying on loopback.
+type netOptions struct {
+	target  string // iperf3 server host
+	bitrate string // iperf3 -b per stream, e.g. "100M"; empty is unlimited
+	url     string // downloaded in parallel when iperf3 can't be used
+}
+
+// bitratePattern matches iperf3's bitrate syntax: a number with an
+// optional K/M/G suffix.
+var bitratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[KMGkmg]?$`)
 
-	if checkTool("iperf3") {
+func (o netOptions) validate() error {
+	if o.bitrate != "" && !bitratePattern.MatchString(o.bitrate) {
+		return fmt.Errorf("--bitrate must be a number with an optional K, M or G suffix, got %q", o.bitrate)
+	}
+	if o.url != "" {
+		u, err := url.Parse(o.url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("--url must be an http or https URL, got %q", o.url)
+		}
+	}
+	return nil
+}
+
+// downloadStreams is how many parallel downloads the HTTP fallback runs.
+const downloadStreams = 8
+
+func stressWifi(secs int, sigCh chan os.Signal, opts netOptions, dryRun bool) {
+	fmt.Printf("  Network stress %s\n", forDuration(secs))
+
+	hasIperf := checkTool("iperf3")
+	switch {
+	case opts.target != "" && hasIperf:
+		fmt.Printf("  iperf3 against %s\n", opts.target)
+		runBench(sigCh, dryRun, "wifi", iperfBench, iperfClientArgs(opts.target, secs, opts.bitrate)...)
+		return
+	case opts.url != "":
+		if opts.target != "" {
+			fmt.Println("  iperf3 not found, downloading instead")
+		}
+		httpDownloads(secs, sigCh, opts.url, dryRun)
+		return
+	case opts.target == "" && hasIperf:
 		fmt.Println("  iperf3 self-test (loopback stress)")
-		server := exec.Command("iperf3", "-s", "-D", "-1")
-		server.Start()
-		time.Sleep(500 * time.Millisecond)
+		if !dryRun {
+			server := exec.Command("iperf3", "-s", "-D", "-1")
+			server.Start()
+			time.Sleep(500 * time.Millisecond)
+		}
 
-		runCmd(sigCh, "iperf3", "-c", "127.0.0.1", "-t", strconv.Itoa(secs), "-P", "8")
+		runBench(sigCh, dryRun, "wifi", iperfBench, iperfClientArgs("127.0.0.1", secs, opts.bitrate)...)
 		return
 	}
 
-	fmt.Println("  Sustained ping flood (requires network)")
-	runCmd(sigCh, "ping", "-f", "-c", strconv.Itoa(secs*1000), "-i", "0.001", "1.1.1.1")
+	host := "1.1.1.1"
+	if opts.target != "" {
+		host = opts.target
+	}
+	fmt.Printf("  Sustained ping flood to %s (requires network)\n", host)
+	args := []string{"-f", "-i", "0.001", host}
+	if secs != forever {
+		args = append([]string{"-c", strconv.Itoa(secs * 1000)}, args...)
+	}
+	runCmd(sigCh, dryRun, "ping", args...)
+}
+
+// iperfClientArgs runs 8 parallel streams; -t 0 transmits until the
+// client is stopped.
+func iperfClientArgs(host string, secs int, bitrate string) []string {
+	args := []string{"-c", host, "-t", strconv.Itoa(secs), "-P", "8"}
+	if bitrate != "" {
+		args = append(args, "-b", bitrate)
+	}
+	return args
+}
+
+// httpDownloads fetches rawURL over and over on downloadStreams
+// connections, discarding the body, until the run ends.
+func httpDownloads(secs int, sigCh chan os.Signal, rawURL string, dryRun bool) {
+	fmt.Printf("  %d parallel downloads of %s\n", downloadStreams, rawURL)
+	if dryRun {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var total atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < downloadStreams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				n, err := download(ctx, rawURL)
+				total.Add(n)
+				if err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "  download: %v\n", err)
+					time.Sleep(time.Second) // don't spin on a dead link
+				}
+			}
+		}()
+	}
+
+	interrupted := false
+	select {
+	case <-sigCh:
+		interrupted = true
+	case <-timeout(secs):
+	}
+	cancel()
+	wg.Wait()
+
+	if interrupted {
+		fmt.Print("\n  interrupted")
+	} else {
+		fmt.Print("  completed")
+	}
+	fmt.Printf(", %s downloaded\n", fmtBytes(uint64(total.Load())))
+}
+
+func download(ctx context.Context, rawURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s", resp.Status)
+	}
+	return io.Copy(io.Discard, resp.Body)
 }
 
 // ── All at once ──────────────────────────────────────────────────────
 
-func stressAll(secs int, sigCh chan os.Signal) {
-	fmt.Printf("  Stressing ALL components for %ds\n\n", secs)
+func stressAll(secs int, sigCh chan os.Signal, cpu cpuOptions, net netOptions, dryRun bool) {
+	fmt.Printf("  Stressing ALL components %s\n\n", forDuration(secs))
 
 	type job struct {
 		name string
@@ -285,41 +664,61 @@ func stressAll(secs int, sigCh chan os.Signal) {
 	}
 
 	jobs := []job{
-		{"CPU", stressCPU},
-		{"GPU", stressGPU},
-		{"NVMe", stressNVMe},
-		{"WiFi", stressWifi},
+		{"CPU", func(secs int, sigCh chan os.Signal) { stressCPU(secs, sigCh, cpu, dryRun) }},
+		{"GPU", func(secs int, sigCh chan os.Signal) { stressGPU(secs, sigCh, dryRun) }},
+		{"NVMe", func(secs int, sigCh chan os.Signal) { stressNVMe(secs, sigCh, dryRun) }},
+		{"WiFi", func(secs int, sigCh chan os.Signal) { stressWifi(secs, sigCh, net, dryRun) }},
+	}
+
+	// One after another, so each plan prints as a block
+	if dryRun {
+		for _, j := range jobs {
+			fmt.Printf("-- %s stress --\n", j.name)
+			j.fn(secs, sigCh)
+			fmt.Println()
+		}
+		return
 	}
 
 	done := make(chan struct{})
 	go func() {
 		select {
 		case <-sigCh:
-		case <-time.After(time.Duration(secs) * time.Second):
+		case <-timeout(secs):
 		}
 		close(done)
 	}()
 
+	// Wait for every job, not just the deadline: fio and iperf3 print
+	// their summaries after SIGTERM, and stressNVMe removes its scratch
+	// files on return
+	var wg sync.WaitGroup
 	for _, j := range jobs {
-		j := j
 		jobSig := make(chan os.Signal, 1)
 		go func() {
 			<-done
 			jobSig <- syscall.SIGTERM
 		}()
+		wg.Add(1)
 		go func() {
+			defer wg.Done()
 			fmt.Printf("-- Starting %s stress --\n", j.name)
 			j.fn(secs, jobSig)
 		}()
 	}
 
-	<-done
-	time.Sleep(500 * time.Millisecond)
+	wg.Wait()
 	fmt.Println("\n  All stress tests complete")
 }
 
 // ── Helpers ──────────────────────────────────────────────────────────
 
+func logEvent(label string) {
+	if err := store.AppendEvent(label, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "  note: could not write event log: %v\n", err)
+	}
+}
+
 func checkTool(name string) bool {
 	_, err := exec.LookPath(name)
 	return err == nil
@@ -339,7 +738,16 @@ func findBlockDev(prefix string) string {
 	return ""
 }
 
-func runCmdWithTimeout(secs int, sigCh chan os.Signal, name string, args ...string) {
+// printCmd shows the command a dry run would have started.
+func printCmd(name string, args []string) {
+	fmt.Printf("  would run: %s %s\n", name, strings.Join(args, " "))
+}
+
+func runCmdWithTimeout(secs int, sigCh chan os.Signal, dryRun bool, name string, args ...string) {
+	if dryRun {
+		printCmd(name, args)
+		return
+	}
 	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -366,7 +774,7 @@ func runCmdWithTimeout(secs int, sigCh chan os.Signal, name string, args ...stri
 		time.Sleep(200 * time.Millisecond)
 		cmd.Process.Kill()
 		fmt.Println("\n  interrupted")
-	case <-time.After(time.Duration(secs) * time.Second):
+	case <-timeout(secs):
 		cmd.Process.Signal(syscall.SIGTERM)
 		time.Sleep(200 * time.Millisecond)
 		cmd.Process.Kill()
@@ -374,9 +782,18 @@ func runCmdWithTimeout(secs int, sigCh chan os.Signal, name string, args ...stri
 	}
 }
 
-func runCmd(sigCh chan os.Signal, name string, args ...string) {
+func runCmd(sigCh chan os.Signal, dryRun bool, name string, args ...string) {
+	runCmdTo(os.Stdout, sigCh, dryRun, name, args...)
+}
+
+// runCmdTo is runCmd with the command's stdout sent to w.
+func runCmdTo(w io.Writer, sigCh chan os.Signal, dryRun bool, name string, args ...string) {
+	if dryRun {
+		printCmd(name, args)
+		return
+	}
 	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = w
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Start(); err != nil {
@@ -403,9 +820,13 @@ func runCmd(sigCh chan os.Signal, name string, args ...string) {
 			fmt.Println("  completed")
 		}
 	case <-sigCh:
+		// Give the tool a moment to write its final report
 		cmd.Process.Signal(syscall.SIGTERM)
-		time.Sleep(200 * time.Millisecond)
-		cmd.Process.Kill()
+		select {
+		case <-cmdDone:
+		case <-time.After(2 * time.Second):
+			cmd.Process.Kill()
+		}
 		fmt.Println("\n  interrupted")
 	}
 }
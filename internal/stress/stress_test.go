@@ -0,0 +1,136 @@
+package stress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseCPUOptions(t *testing.T) {
+	n := runtime.NumCPU()
+	tests := []struct {
+		cpus int
+		load string
+		want cpuOptions
+		ok   bool
+	}{
+		{0, "100%", cpuOptions{cpus: n, load: 100}, true}, // 0 is every core
+		{1, "50%", cpuOptions{cpus: 1, load: 50}, true},
+		{n, "75", cpuOptions{cpus: n, load: 75}, true}, // the percent sign is optional
+		{1, " 1% ", cpuOptions{cpus: 1, load: 1}, true},
+		{-1, "100%", cpuOptions{}, false},
+		{n + 1, "100%", cpuOptions{}, false}, // more cores than the machine has
+		{1, "0%", cpuOptions{}, false},
+		{1, "101%", cpuOptions{}, false},
+		{1, "half", cpuOptions{}, false},
+		{1, "", cpuOptions{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseCPUOptions(tt.cpus, tt.load)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseCPUOptions(%d, %q) = %+v, %v; want %+v, ok %v", tt.cpus, tt.load, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBusyShare(t *testing.T) {
+	for load, want := range map[int]time.Duration{100: dutyPeriod, 50: 50 * time.Millisecond, 1: time.Millisecond} {
+		if got := busyShare(load); got != want {
+			t.Errorf("busyShare(%d) = %v, want %v", load, got, want)
+		}
+	}
+}
+
+func TestPrepareScratchDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sensors-stress-disk")
+	leftover := filepath.Join(dir, "disk-stress.0.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(leftover, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := prepareScratch(dir, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(leftover); err != nil {
+		t.Errorf("a dry run should leave the scratch directory alone: %v", err)
+	}
+
+	if err := prepareScratch(dir, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("a real run should clear files left by an earlier one: %v", err)
+	}
+}
+
+func TestNetOptionsValidate(t *testing.T) {
+	for _, o := range []netOptions{
+		{},
+		{target: "192.168.1.10", bitrate: "100M"},
+		{bitrate: "1.5g"},
+		{bitrate: "750000"},
+		{url: "https://example.com/100MB.bin"},
+	} {
+		if err := o.validate(); err != nil {
+			t.Errorf("%+v: %v", o, err)
+		}
+	}
+	for _, o := range []netOptions{
+		{bitrate: "100Mbit"},
+		{bitrate: "-5M"},
+		{bitrate: "M"},
+		{url: "ftp://example.com/file"},
+		{url: "example.com/file"},
+	} {
+		if err := o.validate(); err == nil {
+			t.Errorf("%+v: expected an error", o)
+		}
+	}
+}
+
+func TestIperfClientArgs(t *testing.T) {
+	tests := []struct {
+		host    string
+		secs    int
+		bitrate string
+		want    []string
+	}{
+		{"127.0.0.1", 60, "", []string{"-c", "127.0.0.1", "-t", "60", "-P", "8"}},
+		{"nas", 30, "100M", []string{"-c", "nas", "-t", "30", "-P", "8", "-b", "100M"}},
+		{"nas", forever, "", []string{"-c", "nas", "-t", "0", "-P", "8"}}, // -t 0 runs until stopped
+	}
+	for _, tt := range tests {
+		if got := iperfClientArgs(tt.host, tt.secs, tt.bitrate); !slices.Equal(got, tt.want) {
+			t.Errorf("iperfClientArgs(%q, %d, %q) = %q, want %q", tt.host, tt.secs, tt.bitrate, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPDownloadsDryRun(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write(make([]byte, 1024))
+	}))
+	defer srv.Close()
+
+	httpDownloads(1, make(chan os.Signal), srv.URL, true)
+	if n := hits.Load(); n != 0 {
+		t.Errorf("dry run made %d requests", n)
+	}
+
+	// The same call without the dry run does reach the server
+	httpDownloads(1, make(chan os.Signal), srv.URL, false)
+	if hits.Load() == 0 {
+		t.Error("a real run made no requests")
+	}
+}
@@ -0,0 +1,131 @@
+package stress
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+)
+
+// traceInterval is how often a trace samples sensors and metrics.
+const traceInterval = time.Second
+
+// metricChip is the chip of stress metric readings in a trace, so they
+// sort together and can be picked out with `--only stress`.
+const metricChip = "stress"
+
+// trace is the running tracer, nil unless --trace was given. Runners
+// publish metrics to it unconditionally; publish on nil does nothing.
+var trace *tracer
+
+// tracer writes sensor readings and the runners' latest metrics (IOPS,
+// bandwidth) into one CSV, one row per value per tick, so temperature and
+// performance line up by timestamp. CPU load comes from /proc/stat like
+// `sensors --cpu-load`.
+type tracer struct {
+	ds      *store.DiskStore
+	path    string
+	metrics chan []sensor.Reading
+	done    chan struct{}
+	samples int
+}
+
+func startTrace(ctx context.Context, path string) (*tracer, error) {
+	ds, err := store.NewFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sensor.EnableCPULoad()
+	t := &tracer{
+		ds:      ds,
+		path:    path,
+		metrics: make(chan []sensor.Reading),
+		done:    make(chan struct{}),
+	}
+	go t.run(ctx)
+	return t, nil
+}
+
+// publish hands the latest values of some metrics to the tracer.
+func (t *tracer) publish(rs []sensor.Reading) {
+	if t == nil || len(rs) == 0 {
+		return
+	}
+	select {
+	case t.metrics <- rs:
+	case <-t.done:
+	}
+}
+
+// run merges metrics into each sensor poll until ctx is cancelled. A
+// metric not refreshed for a few intervals is dropped, so a finished job
+// does not repeat its last value.
+func (t *tracer) run(ctx context.Context) {
+	defer close(t.done)
+	defer t.ds.Close()
+
+	type metric struct {
+		r    sensor.Reading
+		seen time.Time
+	}
+	latest := map[string]metric{}
+	warned := false
+	ticker := time.NewTicker(traceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case rs := <-t.metrics:
+			now := time.Now()
+			for _, r := range rs {
+				latest[r.Key()] = metric{r, now}
+			}
+		case now := <-ticker.C:
+			readings, err := sensor.ReadAllContext(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			// Keep tracing the metrics without temps rather than stop
+			if err != nil && !warned {
+				fmt.Fprintf(os.Stderr, "  trace: reading sensors: %v\n", err)
+				warned = true
+			}
+			keys := make([]string, 0, len(latest))
+			for key, m := range latest {
+				if now.Sub(m.seen) > 3*traceInterval {
+					delete(latest, key)
+					continue
+				}
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				readings = append(readings, latest[key].r)
+			}
+			if len(readings) == 0 {
+				continue
+			}
+			if err := t.ds.Write(readings, now); err != nil {
+				fmt.Fprintf(os.Stderr, "  trace: %v\n", err)
+				continue
+			}
+			t.samples++
+		}
+	}
+}
+
+// wait blocks until the tracer has flushed its file after cancellation.
+func (t *tracer) wait() {
+	<-t.done
+	fmt.Printf("Trace: %d samples written to %s\n", t.samples, t.path)
+}
+
+func metricReading(tool, label string, v float64, unit string) sensor.Reading {
+	return sensor.Reading{Chip: metricChip, Adapter: tool, Label: label, Temp: v, Unit: unit}
+}
@@ -0,0 +1,40 @@
+// Package testutil holds helpers shared by the TUI packages' tests:
+// golden file comparison and SGR escape stripping.
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+// SGR matches one SGR escape sequence, e.g. a color or reset, for tests
+// that count or strip them.
+var SGR = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// CheckGolden compares got with testdata/name.golden, or rewrites the
+// file when the test runs with -update.
+func CheckGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s changed (run go test -update if intended)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
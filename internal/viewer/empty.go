@@ -0,0 +1,71 @@
+package viewer
+
+import (
+	"errors"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/luki/sensors/internal/store"
+)
+
+// ErrNoHistory is returned by Run when the data directory holds no
+// recorded days and there is no terminal to show the empty state on.
+var ErrNoHistory = errors.New("no recorded history")
+
+// emptyModel is shown instead of the viewer before anything has been
+// recorded. It checks for a first day every followInterval and turns
+// into the viewer once one appears, e.g. after `sensors` was started in
+// another terminal.
+type emptyModel struct {
+	opts   Options
+	width  int
+	height int
+}
+
+func (e emptyModel) Init() tea.Cmd {
+	return reloadCmd(0)
+}
+
+func (e emptyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.width, e.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return e, tea.Quit
+		}
+	case reloadMsg:
+		days, err := store.ListDays("")
+		if err != nil || len(days) == 0 {
+			return e, reloadCmd(0)
+		}
+		m := initModel(days, e.opts)
+		return m.Update(tea.WindowSizeMsg{Width: e.width, Height: e.height})
+	}
+	return e, nil
+}
+
+func (e emptyModel) View() string {
+	if e.width == 0 {
+		return "  Loading..."
+	}
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		lipgloss.NewStyle().Foreground(colorTitleFg).Bold(true).
+			Render("No recorded history yet — run `sensors` to start recording"),
+		"",
+		lipgloss.NewStyle().Foreground(colorDim).
+			Render("Data is read from "+store.DataDir()+"; this screen opens the first day as soon as it appears."),
+		lipgloss.NewStyle().Foreground(colorDim).Render("q: quit"),
+	)
+	return lipgloss.Place(e.width, e.height, lipgloss.Center, lipgloss.Center, msg)
+}
+
+// isTerminal reports whether f is a character device, so scripts that run
+// `sensors history` without a terminal get ErrNoHistory instead of a TUI.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
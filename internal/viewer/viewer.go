@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -19,24 +21,79 @@ import (
 	"github.com/luki/sensors/internal/store"
 )
 
-// Run launches the historical data viewer TUI.
-func Run() {
-	days, err := store.ListDays("")
-	if err != nil || len(days) == 0 {
-		fmt.Fprintf(os.Stderr, "No history data found in %s\n", store.DataDir())
-		os.Exit(1)
+// defaultMaxGap is how far from the cursor a sensor's nearest reading
+// may be before it shows "--" instead of a stale value.
+const defaultMaxGap = 2 * time.Minute
+
+// defaultWindow is how much time the sparklines beside the cursor span.
+const defaultWindow = 10 * time.Minute
+
+// followInterval is how often follow mode reloads the newest data.
+const followInterval = 2 * time.Second
+
+// overviewHeight is the number of rows of the o whole-day chart.
+const overviewHeight = 2
+
+// Options configures optional viewer behavior.
+type Options struct {
+	MaxGap time.Duration     // nearest reading further than this shows "--"; 0 uses defaultMaxGap
+	Window time.Duration     // time spanned by each sparkline; 0 uses defaultWindow
+	Only   []string          // substrings of chip/label keys to show; empty shows all
+	File   string            // browse this CSV as a single day instead of the data directory
+	Clock  store.ClockWindow // time of day to load, e.g. 14:00-16:00; zero loads whole days
+	Fresh  bool              // start at the latest day instead of where the last session was left
+}
+
+// Run launches the historical data viewer TUI. With no recorded days it
+// shows an empty-state screen, or returns ErrNoHistory when stdout is not
+// a terminal. A data directory that cannot be read is an error, not an
+// empty one; one that does not exist yet just has nothing recorded.
+func Run(opts Options) error {
+	var tm tea.Model
+	var days []string
+	if opts.File == "" {
+		var err error
+		if days, err = store.ListDays(""); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if opts.File != "" {
+		readings, err := store.LoadFile(opts.File)
+		if err != nil {
+			return err
+		}
+		tm = initFileModel(filepath.Base(opts.File), readings, opts)
+	} else if len(days) == 0 {
+		if !isTerminal(os.Stdout) {
+			return fmt.Errorf("%w in %s", ErrNoHistory, store.DataDir())
+		}
+		tm = emptyModel{opts: opts}
+	} else {
+		m := initModel(days, opts)
+		if !opts.Fresh {
+			m.restore(store.LoadViewerState())
+		}
+		tm = m
 	}
 
 	p := tea.NewProgram(
-		initModel(days),
+		tm,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if fm, ok := final.(model); ok && !fm.singleFile() && len(fm.timeSlots) > 0 {
+		state := store.ViewerState{Day: fm.days[fm.dayIdx], Cursor: fm.timeSlots[fm.cursor]}
+		// Losing the position is not worth failing the command over
+		if err := store.SaveViewerState(state); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: saving viewer position: %v\n", err)
+		}
 	}
+	return nil
 }
 
 // ── Color palette ────────────────────────────────────────────────────
@@ -48,11 +105,16 @@ var (
 	colorChipName = lipgloss.Color("147")
 	colorLabel    = lipgloss.Color("252")
 	colorDim      = lipgloss.Color("240")
+	colorAdapter  = lipgloss.Color("243")
 	colorFooterBg = lipgloss.Color("235")
 	colorWarn     = lipgloss.Color("220")
 	colorCrit     = lipgloss.Color("196")
 )
 
+// now is the clock "Today" and relative times are judged by, replaced in
+// tests for stable output.
+var now = time.Now
+
 // ── Model ────────────────────────────────────────────────────────────
 
 type model struct {
@@ -62,13 +124,27 @@ type model struct {
 	sensors  []string              // unique sensor keys (sorted)
 	cursor   int                   // time cursor position
 	scroll   int                   // vertical scroll offset
+	wrap     bool                  // h/l roll over into adjacent days
+	follow   bool                  // f: reload the newest data and keep the cursor on it
+	overview bool                  // o: whole-day chart under each sensor with its peak marked
 	width    int
 	height   int
 	err      error
 
+	maxGap     time.Duration          // cursor-to-reading distance shown as "--"
+	window     time.Duration          // time spanned by each sparkline
+	only       []string               // key substrings to keep; empty keeps all
+	clock      store.ClockWindow      // time of day each day is cut to
+	preloaded  []store.StoredReading  // single-file mode: the only "day", no day navigation
+	file       string                 // single-file mode: path reloaded by follow mode
+	followGen  int                    // bumped each time f starts following; older ticks are dropped
 	timeSlots  []time.Time            // unique timestamps (sorted)
+	step       time.Duration          // sparkline column width in real time
 	series     map[string][]dataPoint // sensor key -> sorted data points
 	thresholds map[string][2]float64  // sensor key -> [high, crit]
+	units      map[string]string      // sensor key -> unit, "" for temperature
+	adapters   map[string]string      // chip -> adapter or model, e.g. "PCI adapter"
+	events     []store.Event          // annotations for the day, e.g. stress runs
 }
 
 type dataPoint struct {
@@ -76,10 +152,37 @@ type dataPoint struct {
 	temp float64
 }
 
-func initModel(days []string) model {
+// initFileModel browses readings loaded up front, e.g. from a CSV shared
+// by someone else, as a single day called name.
+func initFileModel(name string, readings []store.StoredReading, opts Options) model {
+	if readings == nil {
+		readings = []store.StoredReading{}
+	}
+	m := newModel([]string{name}, readings, opts)
+	m.file = opts.File
+	return m
+}
+
+func initModel(days []string, opts Options) model {
+	return newModel(days, nil, opts)
+}
+
+func newModel(days []string, preloaded []store.StoredReading, opts Options) model {
+	if opts.MaxGap <= 0 {
+		opts.MaxGap = defaultMaxGap
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultWindow
+	}
 	m := model{
 		days:   days,
 		dayIdx: 0,
+		maxGap: opts.MaxGap,
+		window: opts.Window,
+		only:   opts.Only,
+		clock:  opts.Clock,
+
+		preloaded: preloaded,
 	}
 	m.loadDay()
 	return m
@@ -87,28 +190,46 @@ func initModel(days []string) model {
 
 func (m *model) loadDay() {
 	day := m.days[m.dayIdx]
-	readings, err := store.LoadDay(day)
-	if err != nil {
-		m.err = err
-		return
+	readings := m.preloaded
+	if !m.singleFile() {
+		var err error
+		readings, err = store.LoadDay(day)
+		if err != nil {
+			m.err = err
+			return
+		}
 	}
+	readings = m.clock.Filter(store.Dedup(readings))
 	m.readings = readings
 	m.err = nil
 
+	// Slots are keyed by millisecond so sub-second recordings keep every
+	// sample; whole-second files key the same as before
 	timeSet := make(map[int64]time.Time)
 	seriesMap := make(map[string][]dataPoint)
 	threshMap := make(map[string][2]float64)
+	unitMap := make(map[string]string)
+	adapterMap := make(map[string]string)
 	sensorSet := make(map[string]bool)
 
 	for _, r := range readings {
-		key := r.Chip + "/" + r.Label
+		key := r.Key()
+		if !m.keep(key) {
+			continue
+		}
 		sensorSet[key] = true
-		timeSet[r.Time.Unix()] = r.Time
+		timeSet[r.Time.UnixMilli()] = r.Time
 		seriesMap[key] = append(seriesMap[key], dataPoint{time: r.Time, temp: r.Temp})
 
 		if r.High > 0 || r.Crit > 0 {
 			threshMap[key] = [2]float64{r.High, r.Crit}
 		}
+		if r.Unit != "" {
+			unitMap[key] = r.Unit
+		}
+		if r.Adapter != "" {
+			adapterMap[r.Chip] = r.Adapter
+		}
 	}
 
 	var sensors []string
@@ -124,6 +245,7 @@ func (m *model) loadDay() {
 	}
 	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
 	m.timeSlots = times
+	m.step = store.MedianStep(times)
 
 	for k, pts := range seriesMap {
 		sort.Slice(pts, func(i, j int) bool { return pts[i].time.Before(pts[j].time) })
@@ -131,6 +253,13 @@ func (m *model) loadDay() {
 	}
 	m.series = seriesMap
 	m.thresholds = threshMap
+	m.units = unitMap
+	m.adapters = adapterMap
+
+	// A missing or unreadable event file just means no markers
+	if !m.singleFile() {
+		m.events, _ = store.LoadEvents(day)
+	}
 
 	if len(m.timeSlots) > 0 {
 		m.cursor = len(m.timeSlots) - 1
@@ -138,8 +267,123 @@ func (m *model) loadDay() {
 	m.scroll = 0
 }
 
+// clockLayout formats cursor times, showing milliseconds only for data
+// sampled faster than once a second.
+func (m model) clockLayout() string {
+	if m.step < time.Second {
+		return "15:04:05.000"
+	}
+	return "15:04:05"
+}
+
+// restore moves to a saved day and cursor time. A day that is no longer
+// stored is ignored, and the cursor goes to the slot nearest the saved
+// time, since a --since/--until window or new readings may have changed
+// the slots.
+func (m *model) restore(s store.ViewerState) {
+	idx := slices.Index(m.days, s.Day)
+	if idx < 0 {
+		return
+	}
+	if idx != m.dayIdx {
+		m.dayIdx = idx
+		m.loadDay()
+	}
+	if len(m.timeSlots) > 0 && !s.Cursor.IsZero() {
+		m.cursor = nearestSlot(m.timeSlots, s.Cursor)
+	}
+}
+
+// singleFile reports whether the viewer browses one preloaded file, in
+// which case the day navigation keys do nothing.
+func (m model) singleFile() bool {
+	return m.preloaded != nil
+}
+
+// keep reports whether a sensor key passes the Only filter.
+func (m model) keep(key string) bool {
+	if len(m.only) == 0 {
+		return true
+	}
+	for _, p := range m.only {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleEvents returns the events that fall within the day's data.
+func (m model) visibleEvents() []store.Event {
+	if len(m.timeSlots) == 0 {
+		return nil
+	}
+	first, last := m.timeSlots[0], m.timeSlots[len(m.timeSlots)-1]
+	var out []store.Event
+	for _, e := range m.events {
+		if !e.Time.Before(first) && !e.Time.After(last) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// eventTimes returns the times of visibleEvents, for sparkline markers.
+func (m model) eventTimes() []time.Time {
+	var out []time.Time
+	for _, e := range m.visibleEvents() {
+		out = append(out, e.Time)
+	}
+	return out
+}
+
+// slotColumn maps a time to a scrubber column, using the same
+// slot-to-column scaling as renderScrubber.
+func (m model) slotColumn(t time.Time, width int) int {
+	if len(m.timeSlots) <= 1 {
+		return 0
+	}
+	idx := nearestSlot(m.timeSlots, t)
+	return idx * (width - 1) / (len(m.timeSlots) - 1)
+}
+
+// canFollow reports whether there is anything for follow mode to reload:
+// the data directory, or a file given by path rather than preloaded.
+func (m model) canFollow() bool {
+	return !m.singleFile() || m.file != ""
+}
+
+// reload re-reads the newest data for follow mode: the latest day, which
+// may be a new one since midnight, or the --file being written. The
+// cursor goes to the last sample and the scroll position is kept.
+func (m *model) reload() {
+	scroll := m.scroll
+	if m.singleFile() {
+		readings, err := store.LoadFile(m.file)
+		if err != nil {
+			m.err = err
+			return
+		}
+		m.preloaded = readings
+	} else if days, err := store.ListDays(""); err == nil && len(days) > 0 {
+		m.days = days
+		m.dayIdx = 0
+	}
+	m.loadDay()
+	m.scroll = scroll
+}
+
 // ── Init / Update ────────────────────────────────────────────────────
 
+// reloadMsg triggers a follow mode reload. gen is the followGen the tick
+// was scheduled under, so toggling f off and on again does not leave two
+// tick chains running.
+type reloadMsg struct{ gen int }
+
+func reloadCmd(gen int) tea.Cmd {
+	return tea.Tick(followInterval, func(time.Time) tea.Msg { return reloadMsg{gen} })
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
@@ -147,28 +391,65 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
+	case reloadMsg:
+		if !m.follow || msg.gen != m.followGen {
+			return m, nil
+		}
+		m.reload()
+		return m, reloadCmd(m.followGen)
+
 	case tea.KeyMsg:
+		// Moving off the newest sample stops following, like scrolling
+		// back in a pager
+		day, cursor := m.dayIdx, m.cursor
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 
+		case "f":
+			m.follow = !m.follow && m.canFollow()
+			if m.follow {
+				m.followGen++
+				m.reload()
+				return m, reloadCmd(m.followGen)
+			}
+
 		case "left", "h":
 			if m.cursor > 0 {
 				m.cursor--
+			} else if m.wrap && m.dayIdx < len(m.days)-1 {
+				// loadDay leaves the cursor at the end of the previous day
+				m.dayIdx++
+				m.loadDay()
 			}
 		case "right", "l":
 			if m.cursor < len(m.timeSlots)-1 {
 				m.cursor++
+			} else if m.wrap && m.dayIdx > 0 {
+				m.dayIdx--
+				m.loadDay()
+				m.cursor = 0
 			}
+		case "w":
+			m.wrap = !m.wrap && !m.singleFile()
+		case "o":
+			m.overview = !m.overview
 		case "shift+left", "H":
-			m.cursor -= 60
-			if m.cursor < 0 {
-				m.cursor = 0
+			if len(m.timeSlots) > 0 {
+				n := nearestSlot(m.timeSlots, m.timeSlots[m.cursor].Add(-time.Minute))
+				if n == m.cursor && n > 0 {
+					n-- // sparse data: always move at least one slot
+				}
+				m.cursor = n
 			}
 		case "shift+right", "L":
-			m.cursor += 60
-			if m.cursor >= len(m.timeSlots) {
-				m.cursor = len(m.timeSlots) - 1
+			if len(m.timeSlots) > 0 {
+				n := nearestSlot(m.timeSlots, m.timeSlots[m.cursor].Add(time.Minute))
+				if n == m.cursor && n < len(m.timeSlots)-1 {
+					n++
+				}
+				m.cursor = n
 			}
 		case "home":
 			m.cursor = 0
@@ -195,6 +476,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			m.scroll++
 		}
+		if m.dayIdx != day || m.cursor != cursor {
+			m.follow = false
+		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -281,6 +565,12 @@ func (m model) renderTitle(width int) string {
 		Bold(true).
 		Render(day)
 
+	if rel := relativeDay(day, now().In(store.Location())); rel != "" {
+		dayText += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(" (" + rel + ")")
+	}
+
 	nav := lipgloss.NewStyle().
 		Foreground(colorDim).
 		Render(fmt.Sprintf("  [ %d/%d ]", m.dayIdx+1, len(m.days)))
@@ -319,7 +609,14 @@ func (m model) renderCursorInfo(width int) string {
 	ts := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("214")).
 		Bold(true).
-		Render(t.Format("15:04:05"))
+		Render(t.Format(m.clockLayout()))
+
+	today := now().In(store.Location())
+	if relativeDay(m.days[m.dayIdx], today) == "Today" {
+		ts += lipgloss.NewStyle().
+			Foreground(colorDim).
+			Render(" " + relativeTime(t, today))
+	}
 
 	pos := lipgloss.NewStyle().
 		Foreground(colorDim).
@@ -331,9 +628,38 @@ func (m model) renderCursorInfo(width int) string {
 	}
 	scrubber := m.renderScrubber(barWidth)
 
+	prefix := "  " + ts + pos + "  "
+	content := prefix + scrubber
+	if labels := m.renderEventLabels(barWidth); labels != "" {
+		content += "\n" + strings.Repeat(" ", lipgloss.Width(prefix)) + labels
+	}
+
 	return lipgloss.NewStyle().
 		Padding(0, 1).
-		Render("  " + ts + pos + "  " + scrubber)
+		Render(content)
+}
+
+// renderEventLabels writes each event's label starting at its scrubber
+// column, skipping labels that would overlap the previous one.
+func (m model) renderEventLabels(width int) string {
+	visible := m.visibleEvents()
+	if len(visible) == 0 {
+		return ""
+	}
+
+	line := []rune(strings.Repeat(" ", width))
+	lastEnd := -1
+	for _, e := range visible {
+		col := m.slotColumn(e.Time, width)
+		label := []rune("\u2514" + chart.Truncate(e.Label, 14))
+		if col <= lastEnd || col+len(label) > width {
+			continue
+		}
+		copy(line[col:], label)
+		lastEnd = col + len(label)
+	}
+
+	return lipgloss.NewStyle().Foreground(chart.MarkerColor).Render(strings.TrimRight(string(line), " "))
 }
 
 func (m model) renderScrubber(width int) string {
@@ -349,14 +675,22 @@ func (m model) renderScrubber(width int) string {
 		pos = width - 1
 	}
 
+	eventCols := make(map[int]bool)
+	for _, t := range m.eventTimes() {
+		eventCols[m.slotColumn(t, width)] = true
+	}
+
 	var sb strings.Builder
-	dimS := lipgloss.NewStyle().Foreground(lipgloss.Color("237"))
+	dimS := lipgloss.NewStyle().Foreground(chart.DimGray("237"))
 	curS := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
 	tickS := lipgloss.NewStyle().Foreground(lipgloss.Color("239"))
+	markS := lipgloss.NewStyle().Foreground(chart.MarkerColor).Bold(true)
 
 	for i := 0; i < width; i++ {
 		if i == pos {
 			sb.WriteString(curS.Render("\u25C6"))
+		} else if eventCols[i] {
+			sb.WriteString(markS.Render("\u2503"))
 		} else {
 			slotIdx := 0
 			if len(m.timeSlots) > 1 {
@@ -389,7 +723,8 @@ func (m model) renderPanels(totalWidth int) []string {
 		innerWidth = 30
 	}
 
-	chartWidth := innerWidth - 60
+	// Label, value, frame, stats and "H:80° C:100°" take 66 columns
+	chartWidth := innerWidth - 66
 	if chartWidth < 15 {
 		chartWidth = 15
 	}
@@ -400,6 +735,10 @@ func (m model) renderPanels(totalWidth int) []string {
 	labelW := 16
 	tempW := 8
 
+	// Spread the window over the chart; buildSparkWindow averages the
+	// points that share a column. Never finer than the sampling step.
+	colStep := max(m.step, m.window/time.Duration(chartWidth))
+
 	type chipGroup struct {
 		chip    string
 		sensors []string
@@ -419,6 +758,8 @@ func (m model) renderPanels(totalWidth int) []string {
 		g.sensors = append(g.sensors, key)
 	}
 
+	markers := m.eventTimes()
+
 	var panels []string
 
 	for _, chipName := range chipOrder {
@@ -434,16 +775,21 @@ func (m model) renderPanels(totalWidth int) []string {
 		chipID := lipgloss.NewStyle().
 			Foreground(colorDim).
 			Render(g.chip)
-		rows = append(rows, friendlyText+"  "+chipID)
+		header := friendlyText + "  " + chipID
+		if adapter := m.adapters[g.chip]; adapter != "" {
+			header += "  " + lipgloss.NewStyle().Foreground(colorAdapter).Render(adapter)
+		}
+		rows = append(rows, header)
 
 		colLabel := lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Width(labelW).Render("sensor")
 		colVal := lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Width(tempW).Align(lipgloss.Right).Render("value")
-		colHistPad := strings.Repeat(" ", chartWidth/2-3)
-		colHist := lipgloss.NewStyle().Foreground(lipgloss.Color("237")).Render(colHistPad + "history")
+		histTitle := "history (" + fmtWindow(colStep*time.Duration(chartWidth)) + ")"
+		colHistPad := strings.Repeat(" ", max(0, (chartWidth-len(histTitle))/2))
+		colHist := lipgloss.NewStyle().Foreground(chart.DimGray("237")).Render(colHistPad + histTitle)
 		rows = append(rows, colLabel+" "+colVal+"  "+colHist)
 
 		sep := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("237")).
+			Foreground(chart.DimGray("237")).
 			Render(strings.Repeat("\u2500", innerWidth))
 		rows = append(rows, sep)
 
@@ -464,7 +810,9 @@ func (m model) renderPanels(totalWidth int) []string {
 			hasHigh := high > 0
 			hasCrit := crit > 0
 
-			curTemp := findTempAtTime(pts, cursorTime)
+			// Never closer than the sampling step, so sparse recordings
+			// (e.g. `sensors record --interval 5m`) still show values
+			curTemp, hasTemp := findTempAtTime(pts, cursorTime, max(m.maxGap, 3*m.step))
 
 			minV, maxV := math.MaxFloat64, -math.MaxFloat64
 			for _, p := range pts {
@@ -475,29 +823,31 @@ func (m model) renderPanels(totalWidth int) []string {
 					maxV = p.temp
 				}
 			}
-			rangeMin := math.Max(0, minV-5)
-			rangeMax := maxV + 5
-			if hasCrit && crit > rangeMax {
-				rangeMax = crit + 5
-			}
-			if hasHigh && high > rangeMax {
-				rangeMax = high + 5
-			}
+			rangeMin, rangeMax := chart.SensorRange(friendly, m.units[key], minV, maxV, high, crit, hasHigh, hasCrit)
 
-			sparkPts := buildSparkWindow(pts, m.cursor, chartWidth, m.timeSlots)
+			sparkPts := buildSparkWindow(pts, cursorTime, chartWidth, colStep)
 
 			label := lipgloss.NewStyle().
 				Foreground(colorLabel).
 				Bold(true).
 				Width(labelW).
-				Render(truncate(sensorLabel, labelW))
+				Render(chart.Truncate(sensorLabel, labelW))
 
+			value := lipgloss.NewStyle().Foreground(colorDim).Render("--")
+			if hasTemp {
+				value = chart.RenderTempValue(curTemp, high, crit, hasHigh, hasCrit)
+			}
 			temp := lipgloss.NewStyle().
 				Width(tempW).
 				Align(lipgloss.Right).
-				Render(chart.RenderTempValue(curTemp, high, crit, hasHigh, hasCrit))
+				Render(value)
 
-			spark := chart.RenderSparklinePoints(sparkPts, chartWidth, rangeMin, rangeMax, high, crit, hasHigh, hasCrit)
+			// A day with a single sample has nothing to chart or summarize
+			collecting := chart.Collecting(headPoints(pts, 3))
+			spark := chart.RenderCollecting(chartWidth)
+			if !collecting {
+				spark = chart.RenderSparklineMarked(sparkPts, markers, chartWidth, rangeMin, rangeMax, high, crit, hasHigh, hasCrit)
+			}
 
 			frameL := lipgloss.NewStyle().Foreground(colorBorder).Render("\u2595")
 			frameR := lipgloss.NewStyle().Foreground(colorBorder).Render("\u258F")
@@ -511,9 +861,12 @@ func (m model) renderPanels(totalWidth int) []string {
 
 			dimS := lipgloss.NewStyle().Foreground(colorDim)
 			valS := lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
-			stats := dimS.Render("avg") + valS.Render(fmt.Sprintf("%5.1f", avg)) +
-				dimS.Render(" lo") + valS.Render(fmt.Sprintf("%5.1f", minV)) +
-				dimS.Render(" pk") + valS.Render(fmt.Sprintf("%5.1f", maxV))
+			stats := dimS.Render("avg") + valS.Render(chart.Num("%5.1f", avg)) +
+				dimS.Render(" lo") + valS.Render(chart.Num("%5.1f", minV)) +
+				dimS.Render(" pk") + valS.Render(chart.Num("%5.1f", maxV))
+			if collecting {
+				stats = strings.Repeat(" ", lipgloss.Width(stats))
+			}
 
 			var threshTags string
 			if hasHigh {
@@ -527,10 +880,13 @@ func (m model) renderPanels(totalWidth int) []string {
 			rows = append(rows, row)
 
 			timeline := chart.RenderTimeline(sparkPts, chartWidth)
-			if strings.TrimSpace(timeline) != "" {
+			if strings.TrimSpace(timeline) != "" && !collecting {
 				pad := strings.Repeat(" ", labelW+tempW+2)
 				rows = append(rows, pad+" "+timeline)
 			}
+			if m.overview {
+				rows = append(rows, m.renderOverview(pts, m.units[key], chartWidth, labelW+tempW+2, minV, high, crit, hasHigh, hasCrit)...)
+			}
 		}
 
 		panelContent := lipgloss.JoinVertical(lipgloss.Left, rows...)
@@ -554,9 +910,22 @@ func (m model) renderFooter(width int) string {
 	keys := dimS.Render("q") + keyS.Render(":quit") +
 		dimS.Render("  h/l") + keyS.Render(":scrub") +
 		dimS.Render("  H/L") + keyS.Render(":skip 1m") +
-		dimS.Render("  home/end") + keyS.Render(":jump") +
-		dimS.Render("  [/]") + keyS.Render(":day") +
-		dimS.Render("  j/k") + keyS.Render(":scroll")
+		dimS.Render("  home/end") + keyS.Render(":jump")
+	if !m.singleFile() {
+		keys += dimS.Render("  [/]") + keyS.Render(":day") +
+			dimS.Render("  w") + keyS.Render(":wrap")
+	}
+	if m.canFollow() {
+		keys += dimS.Render("  f") + keyS.Render(":follow")
+	}
+	keys += dimS.Render("  o") + keyS.Render(":overview")
+	keys += dimS.Render("  j/k") + keyS.Render(":scroll")
+	if m.wrap {
+		keys += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Render("  WRAP")
+	}
+	if m.follow {
+		keys += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Render("  FOLLOW")
+	}
 
 	return lipgloss.NewStyle().
 		Background(colorFooterBg).
@@ -565,22 +934,139 @@ func (m model) renderFooter(width int) string {
 		Render(keys)
 }
 
+// renderOverview renders a sensor's whole day under its sparkline with a
+// watermark at the day's peak and the peak's value and time beside it.
+// The scale runs from the day's low to just above the peak, so the
+// watermark always sits in the top row.
+func (m model) renderOverview(pts []dataPoint, unit string, width, margin int, low, high, crit float64, hasHigh, hasCrit bool) []string {
+	if len(m.timeSlots) == 0 || len(pts) == 0 {
+		return nil
+	}
+	peak := pts[0]
+	for _, p := range pts[1:] {
+		if p.temp > peak.temp {
+			peak = p
+		}
+	}
+	cols := buildDayOverview(pts, m.timeSlots[0], m.timeSlots[len(m.timeSlots)-1], width)
+	rangeMin, rangeMax := chart.Range(low, peak.temp, unit)
+	bars := chart.RenderWatermark(cols, width, overviewHeight, rangeMin, rangeMax, peak.temp, high, crit, hasHigh, hasCrit)
+
+	frameS := lipgloss.NewStyle().Foreground(colorBorder)
+	dimS := lipgloss.NewStyle().Foreground(colorDim)
+	pad := strings.Repeat(" ", margin)
+	rows := make([]string, len(bars))
+	for i, b := range bars {
+		rows[i] = pad + " " + frameS.Render("\u2595") + b + frameS.Render("\u258F")
+	}
+	value := chart.Num("%.1f", peak.temp) + "\u00b0C"
+	if unit != "" {
+		value = chart.Num("%.1f", peak.temp) + " " + unit
+	}
+	rows[0] += dimS.Render(" \u25b2 " + value + " at " + peak.time.Format(m.clockLayout()))
+	return rows
+}
+
 // ── Helpers ──────────────────────────────────────────────────────────
 
-func findTempAtTime(pts []dataPoint, t time.Time) float64 {
-	best := pts[0].temp
-	bestDiff := absDuration(pts[0].time.Sub(t))
+// buildDayOverview bins the points between first and last into width
+// equal columns. Unlike buildSparkWindow, a column takes the hottest of
+// its points, so a short spike is not averaged away on the overview.
+// Empty columns hold the previous value and leading ones are omitted.
+func buildDayOverview(pts []dataPoint, first, last time.Time, width int) []history.Point {
+	if len(pts) == 0 || width <= 0 {
+		return nil
+	}
+	span := last.Sub(first)
+	maxes := make([]float64, width)
+	seen := make([]bool, width)
 	for _, p := range pts {
-		diff := absDuration(p.time.Sub(t))
-		if diff < bestDiff {
-			bestDiff = diff
-			best = p.temp
+		col := 0
+		if span > 0 {
+			col = int(int64(p.time.Sub(first)) * int64(width-1) / int64(span))
+		}
+		col = max(0, min(width-1, col))
+		if !seen[col] || p.temp > maxes[col] {
+			maxes[col], seen[col] = p.temp, true
+		}
+	}
+
+	var result []history.Point
+	var prev float64
+	have := false
+	for k := range width {
+		if seen[k] {
+			prev, have = maxes[k], true
 		}
-		if p.time.After(t) && diff > bestDiff {
-			break
+		if have {
+			result = append(result, history.Point{Temp: prev, Time: first.Add(span * time.Duration(k) / time.Duration(max(1, width-1)))})
 		}
 	}
-	return best
+	return result
+}
+
+// findTempAtTime returns the temperature of the point nearest to t. It
+// reports false when that point is more than tolerance away, e.g. while a
+// USB drive was unplugged, so the caller can show "no data" rather than a
+// stale value. pts must be sorted by time; ties between an earlier and a
+// later point go to the later one, as in nearestSlot.
+func findTempAtTime(pts []dataPoint, t time.Time, tolerance time.Duration) (float64, bool) {
+	if len(pts) == 0 {
+		return 0, false
+	}
+	i := sort.Search(len(pts), func(i int) bool { return !pts[i].time.Before(t) })
+	if i == len(pts) || (i > 0 && t.Sub(pts[i-1].time) < pts[i].time.Sub(t)) {
+		i--
+	}
+	return pts[i].temp, absDuration(pts[i].time.Sub(t)) <= tolerance
+}
+
+// relativeDay describes a YYYY-MM-DD day relative to now: "Today",
+// "Yesterday" or "N days ago". Returns "" if the day cannot be parsed.
+func relativeDay(day string, now time.Time) string {
+	d, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return ""
+	}
+	// Compare calendar dates in UTC so DST shifts don't skew the day count
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(today.Sub(d).Hours() / 24)
+
+	switch {
+	case days == 0:
+		return "Today"
+	case days == 1:
+		return "Yesterday"
+	case days > 1:
+		return fmt.Sprintf("%d days ago", days)
+	default:
+		return ""
+	}
+}
+
+// relativeTime describes t as an offset before now, e.g. "2h ago".
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm ago", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// nearestSlot returns the index of the time slot closest to t.
+func nearestSlot(slots []time.Time, t time.Time) int {
+	i := sort.Search(len(slots), func(i int) bool { return !slots[i].Before(t) })
+	if i == len(slots) {
+		return len(slots) - 1
+	}
+	if i > 0 && t.Sub(slots[i-1]) < slots[i].Sub(t) {
+		return i - 1
+	}
+	return i
 }
 
 func absDuration(d time.Duration) time.Duration {
@@ -590,45 +1076,79 @@ func absDuration(d time.Duration) time.Duration {
 	return d
 }
 
-func buildSparkWindow(pts []dataPoint, cursorIdx int, width int, timeSlots []time.Time) []history.Point {
-	if len(pts) == 0 || len(timeSlots) == 0 {
+// headPoints converts up to the first n points, enough for checks such as
+// chart.Collecting that only look at the start of a series.
+func headPoints(pts []dataPoint, n int) []history.Point {
+	out := make([]history.Point, 0, min(n, len(pts)))
+	for _, p := range pts[:min(n, len(pts))] {
+		out = append(out, history.Point{Temp: p.temp, Time: p.time})
+	}
+	return out
+}
+
+// buildSparkWindow bins the points ending at cursorTime into width columns
+// of step duration each, so columns always span equal real time no matter
+// how irregularly the data was sampled. Columns take the average of their
+// points; empty columns hold the previous value, and columns before the
+// first available point are omitted so the sparkline pads them.
+func buildSparkWindow(pts []dataPoint, cursorTime time.Time, width int, step time.Duration) []history.Point {
+	if len(pts) == 0 || width <= 0 || step <= 0 {
 		return nil
 	}
 
-	cursorTime := timeSlots[cursorIdx]
+	// Column k covers (start+(k-1)*step, start+k*step]
+	start := cursorTime.Add(-time.Duration(width-1) * step)
+	lower := start.Add(-step)
+	sums := make([]float64, width)
+	counts := make([]int, width)
 
-	tempMap := make(map[int64]float64)
-	for _, p := range pts {
-		tempMap[p.time.Unix()] = p.temp
+	i := sort.Search(len(pts), func(i int) bool { return pts[i].time.After(lower) })
+
+	var last float64
+	have := false
+	if i > 0 {
+		last = pts[i-1].temp
+		have = true
 	}
 
-	var result []history.Point
-	for i := width - 1; i >= 0; i-- {
-		slotIdx := cursorIdx - i
-		if slotIdx < 0 || slotIdx >= len(timeSlots) {
-			continue
-		}
-		t := timeSlots[slotIdx]
-		if temp, ok := tempMap[t.Unix()]; ok {
-			result = append(result, history.Point{Temp: temp, Time: t})
+	for ; i < len(pts) && !pts[i].time.After(cursorTime); i++ {
+		col := int((pts[i].time.Sub(start) + step - 1) / step)
+		if col < 0 {
+			col = 0
 		}
+		sums[col] += pts[i].temp
+		counts[col]++
 	}
 
-	if temp, ok := tempMap[cursorTime.Unix()]; ok {
-		if len(result) == 0 || result[len(result)-1].Time != cursorTime {
-			result = append(result, history.Point{Temp: temp, Time: cursorTime})
+	var result []history.Point
+	for k := 0; k < width; k++ {
+		if counts[k] > 0 {
+			last = sums[k] / float64(counts[k])
+			have = true
 		}
+		if !have {
+			continue
+		}
+		result = append(result, history.Point{Temp: last, Time: start.Add(time.Duration(k) * step)})
 	}
 
 	return result
 }
 
-func truncate(s string, w int) string {
-	if len(s) <= w {
-		return s
-	}
-	if w <= 3 {
-		return s[:w]
+// fmtWindow formats a sparkline span compactly, e.g. "10m", "1h30m" or
+// "45s".
+func fmtWindow(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm%ds", d/time.Minute, (d%time.Minute)/time.Second)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
 	}
-	return s[:w-1] + "\u2026"
 }
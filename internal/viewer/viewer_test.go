@@ -0,0 +1,378 @@
+package viewer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/luki/sensors/internal/demo"
+	"github.com/luki/sensors/internal/sensor"
+	"github.com/luki/sensors/internal/store"
+	"github.com/luki/sensors/internal/testutil"
+)
+
+func TestBuildSparkWindowIrregularIntervals(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+
+	// 10 points at 1s, then 4 points at 5s
+	var pts []dataPoint
+	for i := 0; i < 10; i++ {
+		pts = append(pts, dataPoint{time: base.Add(time.Duration(i) * time.Second), temp: 40})
+	}
+	for i := 1; i <= 4; i++ {
+		pts = append(pts, dataPoint{time: base.Add(9*time.Second + time.Duration(i)*5*time.Second), temp: 60})
+	}
+	cursor := pts[len(pts)-1].time
+
+	win := buildSparkWindow(pts, cursor, 40, time.Second)
+	if len(win) != 30 {
+		t.Fatalf("expected 30 columns spanning 29s of data, got %d", len(win))
+	}
+
+	hot := 0
+	for _, p := range win {
+		if p.Temp == 60 {
+			hot++
+		}
+	}
+	// The 5s samples at 14s..29s span 16 columns; 10s..13s hold the last
+	// 1s-region value. Slot-indexed windows would give them only 4 columns.
+	if hot != 16 {
+		t.Errorf("expected the 5s region to span 16 columns, got %d", hot)
+	}
+	if !win[len(win)-1].Time.Equal(cursor) {
+		t.Errorf("last column should end at the cursor, got %v", win[len(win)-1].Time)
+	}
+}
+
+func TestFindTempAtTimeGap(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	// A drive that was unplugged between 14:01 and 15:00
+	pts := []dataPoint{
+		{time: base, temp: 40},
+		{time: base.Add(time.Minute), temp: 41},
+		{time: base.Add(time.Hour), temp: 35},
+	}
+
+	if temp, ok := findTempAtTime(pts, base.Add(30*time.Minute), 2*time.Minute); ok {
+		t.Errorf("mid-gap cursor: got %f, want no data", temp)
+	}
+	if temp, ok := findTempAtTime(pts, base.Add(90*time.Second), 2*time.Minute); !ok || temp != 41 {
+		t.Errorf("near the last reading: got %f (ok=%v), want 41", temp, ok)
+	}
+	if _, ok := findTempAtTime(nil, base, time.Minute); ok {
+		t.Error("empty series reported data")
+	}
+}
+
+func TestFindTempAtTime(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var pts []dataPoint
+	for i := 0; i < 10; i++ {
+		pts = append(pts, dataPoint{time: base.Add(time.Duration(i) * 10 * time.Second), temp: 40 + float64(i)})
+	}
+
+	tests := []struct {
+		name   string
+		cursor time.Time
+		want   float64
+	}{
+		{"before start", base.Add(-5 * time.Second), 40},
+		{"at start", base, 40},
+		{"just after start", base.Add(4 * time.Second), 40},
+		{"closer to second", base.Add(6 * time.Second), 41},
+		{"exact match", base.Add(50 * time.Second), 45},
+		{"midway takes later", base.Add(55 * time.Second), 46},
+		{"at end", base.Add(90 * time.Second), 49},
+		{"after end", base.Add(95 * time.Second), 49},
+	}
+	for _, tt := range tests {
+		got, ok := findTempAtTime(pts, tt.cursor, time.Minute)
+		if !ok || got != tt.want {
+			t.Errorf("%s: got %f (ok=%v), want %f", tt.name, got, ok, tt.want)
+		}
+	}
+
+	one := pts[:1]
+	if got, ok := findTempAtTime(one, base.Add(30*time.Second), time.Minute); !ok || got != 40 {
+		t.Errorf("single point: got %f (ok=%v), want 40", got, ok)
+	}
+}
+
+func TestFmtWindow(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{10 * time.Minute, "10m"},
+		{599999 * time.Millisecond, "10m"},
+		{90 * time.Second, "1m30s"},
+		{2 * time.Hour, "2h"},
+		{150 * time.Minute, "2h30m"},
+	}
+	for _, tt := range tests {
+		if got := fmtWindow(tt.d); got != tt.want {
+			t.Errorf("fmtWindow(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDayOnly(t *testing.T) {
+	store.SetDataDir(t.TempDir())
+	defer store.SetDataDir("")
+
+	ds, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	ds.Write([]sensor.Reading{
+		{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50},
+		{Chip: "coretemp-isa-0000", Label: "Core 1", Temp: 51},
+		{Chip: "nvme-pci-0300", Label: "Composite", Temp: 40},
+	}, now)
+	ds.Close()
+
+	m := initModel([]string{"2026-02-21"}, Options{Only: []string{"coretemp"}})
+	if m.err != nil {
+		t.Fatalf("loadDay: %v", m.err)
+	}
+	if len(m.sensors) != 2 || len(m.series) != 2 {
+		t.Errorf("expected only the 2 coretemp sensors, got %v", m.sensors)
+	}
+	if _, ok := m.series["nvme-pci-0300/Composite"]; ok {
+		t.Error("filtered sensor still loaded")
+	}
+}
+
+func TestRestoreState(t *testing.T) {
+	store.SetDataDir(t.TempDir())
+	defer store.SetDataDir("")
+
+	ds, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	day1 := time.Date(2026, 2, 20, 14, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	for i := 0; i < 10; i++ {
+		for _, d := range []time.Time{day1, day2} {
+			ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}, d.Add(time.Duration(i)*time.Minute))
+		}
+	}
+	ds.Close()
+
+	if err := store.SaveViewerState(store.ViewerState{Day: "2026-02-20", Cursor: day1.Add(3*time.Minute + 10*time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	m := initModel([]string{"2026-02-21", "2026-02-20"}, Options{})
+	m.restore(store.LoadViewerState())
+	if m.dayIdx != 1 || m.cursor != 3 {
+		t.Errorf("restored to day %d slot %d, want day 1 slot 3", m.dayIdx, m.cursor)
+	}
+
+	// A day that has since been deleted leaves the latest day open
+	m = initModel([]string{"2026-02-21", "2026-02-20"}, Options{})
+	m.restore(store.ViewerState{Day: "2026-01-01", Cursor: day1})
+	if m.dayIdx != 0 || m.cursor != 9 {
+		t.Errorf("unknown day: at day %d slot %d, want day 0 slot 9", m.dayIdx, m.cursor)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	store.SetDataDir(t.TempDir())
+	defer store.SetDataDir("")
+
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	write := func(from, to int) {
+		ds, err := store.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ds.Close()
+		for i := from; i < to; i++ {
+			ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}, base.Add(time.Duration(i)*time.Second))
+		}
+	}
+	write(0, 5)
+
+	var tm tea.Model = initModel([]string{"2026-02-21"}, Options{})
+	tm, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !tm.(model).follow || cmd == nil {
+		t.Fatal("f should start following with a reload tick")
+	}
+
+	write(5, 8)
+	tm, cmd = tm.Update(reloadMsg{gen: 1})
+	if m := tm.(model); len(m.timeSlots) != 8 || m.cursor != 7 || cmd == nil {
+		t.Errorf("after reload: %d slots, cursor %d, want 8 and 7", len(m.timeSlots), m.cursor)
+	}
+
+	// Scrubbing back stops following, and the pending tick then ends
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if tm.(model).follow {
+		t.Error("moving the cursor should stop following")
+	}
+	if _, cmd = tm.Update(reloadMsg{gen: 1}); cmd != nil {
+		t.Error("a reload tick after following stopped should not reschedule")
+	}
+}
+
+func TestFollowToggle(t *testing.T) {
+	store.SetDataDir(t.TempDir())
+	defer store.SetDataDir("")
+
+	ds, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}, time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local))
+	ds.Close()
+
+	// f, f, f within one interval leaves the first tick in flight; only
+	// the chain started by the last f may keep rescheduling
+	f := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")}
+	var tm tea.Model = initModel([]string{"2026-02-21"}, Options{})
+	tm, _ = tm.Update(f)
+	tm, _ = tm.Update(f)
+	tm, _ = tm.Update(f)
+	if !tm.(model).follow {
+		t.Fatal("an odd number of f presses should leave follow on")
+	}
+	if _, cmd := tm.Update(reloadMsg{gen: 1}); cmd != nil {
+		t.Error("a tick from an earlier follow should be dropped")
+	}
+	if _, cmd := tm.Update(reloadMsg{gen: 2}); cmd == nil {
+		t.Error("the current follow's tick should reschedule")
+	}
+}
+
+func TestDayOverview(t *testing.T) {
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	var readings []store.StoredReading
+	for i := 0; i < 100; i++ {
+		temp := 40.0
+		if i == 42 {
+			temp = 90 // a one-sample spike an average would hide
+		}
+		readings = append(readings, store.StoredReading{Time: base.Add(time.Duration(i) * time.Second), Chip: "coretemp-isa-0000", Label: "Core 0", Temp: temp})
+	}
+	m := initFileModel("spike.csv", readings, Options{})
+
+	pts := m.series["coretemp-isa-0000/Core 0"]
+	cols := buildDayOverview(pts, m.timeSlots[0], m.timeSlots[len(m.timeSlots)-1], 20)
+	if len(cols) != 20 || cols[8].Temp != 90 {
+		t.Errorf("spike column = %v, want 90 in column 8 of 20", cols)
+	}
+
+	rows := m.renderOverview(pts, "", 20, 4, 40, 0, 0, false, false)
+	if len(rows) != overviewHeight {
+		t.Fatalf("got %d rows, want %d", len(rows), overviewHeight)
+	}
+	top := testutil.SGR.ReplaceAllString(rows[0], "")
+	if !strings.Contains(top, "───") || !strings.HasSuffix(top, "▲ 90.0°C at 14:00:42") {
+		t.Errorf("top row = %q, want the watermark and the peak label", top)
+	}
+}
+
+func TestInitFileModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.csv")
+	ds, err := store.NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local)
+	ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}, now)
+	ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 52}}, now.Add(time.Second))
+	ds.Close()
+
+	readings, err := store.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := initFileModel("trace.csv", readings, Options{})
+	if m.err != nil {
+		t.Fatalf("loadDay: %v", m.err)
+	}
+	if !m.singleFile() || len(m.days) != 1 {
+		t.Fatalf("expected single-file mode with one day, got %v", m.days)
+	}
+	if len(m.series["coretemp-isa-0000/Core 0"]) != 2 {
+		t.Errorf("expected 2 points, got %v", m.series)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if next.(model).wrap {
+		t.Error("w should not enable day wrap in single-file mode")
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer func(clock func() time.Time) { now = clock }(now)
+	now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local) }
+
+	// A synthetic day with a CPU stress run from 13:34 to 13:42
+	samples, _ := demo.Day(time.Date(2026, 2, 21, 0, 0, 0, 0, time.Local), 10*time.Second, 1)
+	var readings []store.StoredReading
+	for _, s := range samples {
+		for _, r := range s.Readings {
+			readings = append(readings, store.StoredReading{
+				Time: s.Time, Chip: r.Chip, Label: r.Label, Temp: r.Temp, High: r.High, Crit: r.Crit,
+			})
+		}
+	}
+
+	for _, width := range []int{100, 160} {
+		m := initFileModel("demo.csv", readings, Options{})
+		m.width, m.height = width, 30
+		m.cursor = (13*60 + 45) * 6
+		testutil.CheckGolden(t, fmt.Sprintf("view-%d", width), m.View())
+	}
+}
+
+func TestEmptyState(t *testing.T) {
+	store.SetDataDir(t.TempDir())
+	defer store.SetDataDir("")
+
+	var tm tea.Model = emptyModel{}
+	if tm.Init() == nil {
+		t.Fatal("empty state should schedule a check for recorded days")
+	}
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	if view := tm.View(); !strings.Contains(view, "No recorded history yet") {
+		t.Errorf("empty state view missing message:\n%s", view)
+	}
+	tm, cmd := tm.Update(reloadMsg{})
+	if _, ok := tm.(emptyModel); !ok || cmd == nil {
+		t.Fatal("with no days the empty state should stay and check again")
+	}
+
+	ds, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Write([]sensor.Reading{{Chip: "coretemp-isa-0000", Label: "Core 0", Temp: 50}}, time.Date(2026, 2, 21, 14, 0, 0, 0, time.Local))
+	ds.Close()
+
+	tm, _ = tm.Update(reloadMsg{})
+	m, ok := tm.(model)
+	if !ok {
+		t.Fatalf("first recorded day should open the viewer, got %T", tm)
+	}
+	if m.width != 100 || len(m.days) != 1 || len(m.timeSlots) != 1 {
+		t.Errorf("viewer opened with width %d, days %v, %d slots", m.width, m.days, len(m.timeSlots))
+	}
+
+	if _, cmd := (emptyModel{}).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q should quit the empty state")
+	}
+}